@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -21,6 +22,9 @@ import (
 
 var version = "0.1.0"
 
+// sourceFilename names request bodies in parse errors and positions.
+const sourceFilename = "source.go"
+
 func getAllowedOrigins() []string {
 	if env := os.Getenv("ALLOWED_ORIGINS"); env != "" {
 		return strings.Split(env, ",")
@@ -214,15 +218,12 @@ func (s *server) handleGenerateRepo(w http.ResponseWriter, r *http.Request) {
 		req.Package = "repository"
 	}
 
-	name, cleanup, err := s.parseSourceToTemp(req.Source)
-	if err != nil {
-		writeError(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	defer cleanup()
+	src := []byte(req.Source)
 
 	// 1. Parse entities
-	result, err := s.queryHandler.Parse(r.Context(), &query.ParseQuery{Files: []string{name}})
+	result, err := s.queryHandler.Parse(r.Context(), &query.ParseQuery{
+		Sources: []query.Source{{Filename: sourceFilename, Content: src}},
+	})
 	if err != nil {
 		writeError(w, err.Error(), http.StatusUnprocessableEntity)
 		return
@@ -231,10 +232,14 @@ func (s *server) handleGenerateRepo(w http.ResponseWriter, r *http.Request) {
 		writeError(w, "no exported structs found in the provided source", http.StatusUnprocessableEntity)
 		return
 	}
+	if err := diagnosticsError(result.Diagnostics); err != nil {
+		writeError(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
 	ent := result.EntityList[0]
 
 	// 2. Parse interfaces
-	repos, err := s.parserWrapper.ParseInterfaces(r.Context(), []string{name}, ent)
+	repos, err := s.parserWrapper.ParseSourceInterfaces(r.Context(), sourceFilename, src, ent)
 	if err != nil {
 		writeError(w, err.Error(), http.StatusUnprocessableEntity)
 		return
@@ -260,43 +265,38 @@ func (s *server) handleGenerateRepo(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, map[string]string{"output": output}, http.StatusOK)
 }
 
-// parseSourceToTemp writes source to a temp file and returns the filename and a cleanup function.
-func (s *server) parseSourceToTemp(source string) (string, func(), error) {
-	tmp, err := os.CreateTemp("", "structify_*.go")
-	if err != nil {
-		return "", nil, fmt.Errorf("internal error: could not create temp file")
-	}
-	name := tmp.Name()
-
-	if _, err := tmp.WriteString(source); err != nil {
-		tmp.Close()
-		os.Remove(name)
-		return "", nil, fmt.Errorf("internal error: could not write temp file")
-	}
-	tmp.Close()
-
-	cleanup := func() { os.Remove(name) }
-	return name, cleanup, nil
-}
-
 // parseSource parses source string into entities.
 func (s *server) parseSource(ctx context.Context, source string) ([]*entity.Entity, error) {
-	name, cleanup, err := s.parseSourceToTemp(source)
-	if err != nil {
-		return nil, err
-	}
-	defer cleanup()
-
-	result, err := s.queryHandler.Parse(ctx, &query.ParseQuery{Files: []string{name}})
+	result, err := s.queryHandler.Parse(ctx, &query.ParseQuery{
+		Sources: []query.Source{{Filename: sourceFilename, Content: []byte(source)}},
+	})
 	if err != nil {
 		return nil, err
 	}
 	if result.Count == 0 {
 		return nil, fmt.Errorf("no exported structs found in the provided source")
 	}
+	if err := diagnosticsError(result.Diagnostics); err != nil {
+		return nil, err
+	}
 
 	return result.EntityList, nil
 }
+
+// diagnosticsError joins the error diagnostics into one error, one per line.
+func diagnosticsError(diagnostics []entity.Diagnostic) error {
+	var msgs []string
+	for _, d := range diagnostics {
+		if d.Severity == entity.SeverityError {
+			msgs = append(msgs, d.String())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(msgs, "\n"))
+}
+
 func decodeBody(w http.ResponseWriter, r *http.Request, dst any) error {
 	if r.ContentLength > 500*1024 {
 		writeError(w, "request body too large (max 500 KB)", http.StatusRequestEntityTooLarge)
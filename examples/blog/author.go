@@ -17,7 +17,7 @@ import (
 //   - json.RawMessage: maps to JSONB
 //   - time.Time: maps to TIMESTAMPTZ
 type Author struct {
-	ID        int64           `db:"pk"`
+	ID        int64           `db:"pk,identity"`
 	Email     string          `db:"unique,check:email ~* '^[^@]+@[^@]+\\.[^@]+$'"`
 	Username  string          `db:"unique_index:idx_author_username,check:length(username) >= 3"`
 	FullName  string          `db:"check:length(full_name) > 0"`
@@ -12,7 +12,7 @@ import "time"
 //   - check constraint with function call
 //   - default false / default 0
 type Comment struct {
-	ID        int64     `db:"pk"`
+	ID        int64     `db:"pk,identity"`
 	PostID    int64     `db:"fk:post,id,on_delete:CASCADE"`
 	AuthorID  int64     `db:"fk:author,id,on_delete:SET_NULL"`
 	ParentID  int64     `db:"fk:comment,id,on_delete:CASCADE"`
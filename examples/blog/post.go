@@ -13,7 +13,7 @@ import "time"
 //   - bool column with default
 //   - nullable time.Time (no default → nullable in SQL)
 type Post struct {
-	ID          int64     `db:"pk"`
+	ID          int64     `db:"pk,identity"`
 	AuthorID    int64     `db:"fk:author,id,on_delete:CASCADE"`
 	CategoryID  int64     `db:"fk:category,id,on_delete:SET_NULL"`
 	Title       string    `db:"check:length(title) >= 5"`
@@ -35,7 +35,7 @@ type Post struct {
 //   - unique_index for index-backed uniqueness
 //   - default literal and integer default
 type Category struct {
-	ID          int64  `db:"pk"`
+	ID          int64  `db:"pk,identity"`
 	Name        string `db:"unique,check:length(name) > 0"`
 	Slug        string `db:"unique_index:idx_category_slug,check:length(slug) > 0"`
 	Description string
@@ -48,7 +48,7 @@ type Category struct {
 // Demonstrates:
 //   - two independent unique columns on the same struct
 type Tag struct {
-	ID   int64  `db:"pk"`
+	ID   int64  `db:"pk,identity"`
 	Name string `db:"unique,check:length(name) > 0"`
 	Slug string `db:"unique"`
 }
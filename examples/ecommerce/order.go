@@ -10,7 +10,7 @@ import "time"
 //   - default for epoch-style timestamp
 //   - nullable fields (ShippedAt, DeliveredAt have no default → NULL)
 type Order struct {
-	ID          int64   `db:"pk"`
+	ID          int64   `db:"pk,identity"`
 	CustomerID  int64   `db:"fk:customer,id,on_delete:CASCADE"`
 	Status      string  `db:"default:'pending',enum:pending,confirmed,shipped,delivered,cancelled,refunded"`
 	TotalAmount float64 `db:"check:total_amount >= 0"`
@@ -28,7 +28,7 @@ type Order struct {
 //   - enum with default
 //   - default on bool and timestamp
 type Customer struct {
-	ID        int64     `db:"pk"`
+	ID        int64     `db:"pk,identity"`
 	Email     string    `db:"unique,check:email ~* '^[^@]+@[^@]+\\.[^@]+$'"`
 	Username  string    `db:"unique_index:idx_customer_username"`
 	FullName  string    `db:"check:length(full_name) > 0"`
@@ -15,7 +15,7 @@ import (
 //   - json.RawMessage for flexible attribute storage (maps to JSONB)
 //   - time.Time for timestamps
 type Product struct {
-	ID           int64           `db:"pk"`
+	ID           int64           `db:"pk,identity"`
 	CategoryID   int64           `db:"fk:product_category,id,on_delete:RESTRICT"`
 	SKU          string          `db:"unique,check:length(sku) > 0"`
 	Name         string          `db:"index:idx_product_name,check:length(name) > 0"`
@@ -36,7 +36,7 @@ type Product struct {
 //   - self-referential FK (parent category)
 //   - default values
 type ProductCategory struct {
-	ID          int64  `db:"pk"`
+	ID          int64  `db:"pk,identity"`
 	Name        string `db:"unique,check:length(name) > 0"`
 	Slug        string `db:"unique_index:idx_category_slug"`
 	Description string
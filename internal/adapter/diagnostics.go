@@ -0,0 +1,353 @@
+package adapter
+
+import (
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/n0xum/structify/internal/domain/entity"
+	"github.com/n0xum/structify/internal/parser"
+)
+
+// simpleTagKeys are the db tag keys that take no value.
+var simpleTagKeys = map[string]bool{
+	"pk":           true,
+	"unique":       true,
+	"-":            true,
+	"index":        true,
+	"unique_index": true,
+	"embed":        true,
+	"null":         true,
+	"notnull":      true,
+	"identity":     true,
+	"serial":       true,
+	"bigserial":    true,
+	"desc":         true,
+	"nulls_last":   true,
+}
+
+// identityTags are the simple db tag keys that let the database generate a
+// column value.
+var identityTags = []string{"identity", "serial", "bigserial"}
+
+// integerTypes are the Go types an identity column can be scanned into.
+var integerTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+}
+
+// valueTagKeys are the db tag keys written as key:value.
+var valueTagKeys = map[string]bool{
+	"check":        true,
+	"default":      true,
+	"enum":         true,
+	"index":        true,
+	"unique_index": true,
+	"unique":       true,
+	"fk":           true,
+	"table":        true,
+	"prefix":       true,
+	"column":       true,
+	"type":         true,
+	"size":         true,
+	"precision":    true,
+	"scale":        true,
+	"generated":    true,
+	"using":        true,
+	"where":        true,
+	"include":      true,
+	"expr":         true,
+	"order":        true,
+}
+
+// indexMethods are the accepted index access methods.
+var indexMethods = map[string]bool{
+	"btree":  true,
+	"hash":   true,
+	"gist":   true,
+	"spgist": true,
+	"gin":    true,
+	"brin":   true,
+}
+
+// indexOptionKeys are the db tag keys that only apply to an index.
+var indexOptionKeys = []string{"using", "where", "include", "expr", "desc", "nulls_last", "order"}
+
+// fkActions are the accepted on_delete/on_update actions; underscores stand
+// for spaces (SET_NULL).
+var fkActions = map[string]bool{
+	"CASCADE":     true,
+	"RESTRICT":    true,
+	"SET NULL":    true,
+	"SET DEFAULT": true,
+	"NO ACTION":   true,
+}
+
+// diagnoseField reports malformed db tags on a field: unknown keys, foreign
+// keys with the wrong number of parts or an invalid action, and
+// contradictory keys such as pk combined with "-".
+func (a *ParserAdapter) diagnoseField(pField parser.Field) []entity.Diagnostic {
+	var diags []entity.Diagnostic
+	report := func(part string, format string, args ...any) {
+		diags = append(diags, entity.Diagnostic{
+			Pos:      a.tagPartPos(pField, part),
+			Severity: entity.SeverityError,
+			Message:  fmt.Sprintf("field %s: ", pField.Name) + fmt.Sprintf(format, args...),
+		})
+	}
+
+	tags := a.parseTags(pField.DatabaseTag)
+	for _, tag := range tags {
+		key, value, hasValue := strings.Cut(tag, ":")
+		switch {
+		case hasValue && valueTagKeys[key]:
+			switch key {
+			case "fk":
+				a.diagnoseForeignKey(tag, value, report)
+			case "type":
+				if strings.TrimSpace(value) == "" {
+					report(tag, "db tag %q needs a SQL type", key)
+				}
+			case "generated":
+				if strings.TrimSpace(value) == "" {
+					report(tag, "db tag %q needs an expression", key)
+				}
+			case "using":
+				if !indexMethods[strings.ToLower(value)] {
+					report(tag, "invalid index method %q (want btree, hash, gist, spgist, gin or brin)", value)
+				}
+			case "where", "include", "expr":
+				if strings.TrimSpace(value) == "" {
+					report(tag, "db tag %q needs a value", key)
+				}
+			case "size", "precision", "scale", "order":
+				if n, err := strconv.Atoi(value); err != nil || n < 0 || (n == 0 && key != "scale") {
+					report(tag, "db tag %q needs a positive integer, got %q", key, value)
+				}
+			}
+		case !hasValue && simpleTagKeys[key]:
+		case hasValue && simpleTagKeys[key]:
+			report(tag, "db tag %q takes no value", key)
+		default:
+			if suggestion := suggestTagKey(key); suggestion != "" {
+				report(tag, "unknown db tag key %q (did you mean %q?)", key, suggestion)
+			} else {
+				report(tag, "unknown db tag key %q", key)
+			}
+		}
+	}
+
+	precision, hasPrecision := tagInt(tags, "precision")
+	scale, hasScale := tagInt(tags, "scale")
+	if hasScale {
+		if !hasPrecision {
+			report("scale:", `db tag "scale" requires "precision"`)
+		} else if scale > precision {
+			report("scale:", "scale %d exceeds precision %d", scale, precision)
+		}
+	}
+	size, hasSize := tagInt(tags, "size")
+	a.diagnoseTypeModifiers(pField, tags, hasSize && size > 0, hasPrecision && precision > 0,
+		hasScale && scale >= 0 && hasPrecision && precision > 0, report)
+
+	if a.hasTag(tags, "-") && a.hasTag(tags, "pk") {
+		report("pk", `db tag "pk" cannot be combined with "-"`)
+	}
+	var identities []string
+	for _, identity := range identityTags {
+		if a.hasTag(tags, identity) {
+			identities = append(identities, identity)
+		}
+	}
+	switch {
+	case len(identities) > 1:
+		report(identities[1], "db tag %q cannot be combined with %q", identities[1], identities[0])
+	case len(identities) == 1 && !isIntegerField(pField):
+		report(identities[0], "db tag %q requires an integer field, got %s", identities[0], pField.Type)
+	}
+
+	if !a.hasIndexTag(tags) {
+		for _, key := range indexOptionKeys {
+			if tag := a.tagWithKey(tags, key); tag != "" {
+				report(tag, "db tag %q requires index or unique_index", key)
+			}
+		}
+	}
+
+	if generated := a.tagWithPrefix(tags, "generated:"); generated != "" {
+		if a.tagWithPrefix(tags, "default:") != "" {
+			report(generated, `db tag "generated" cannot be combined with "default"`)
+		}
+		if len(identities) > 0 {
+			report(generated, "db tag %q cannot be combined with %q", "generated", identities[0])
+		}
+	}
+
+	if a.hasTag(tags, "null") {
+		if a.hasTag(tags, "notnull") {
+			report("null", `db tag "null" cannot be combined with "notnull"`)
+		}
+		if a.hasTag(tags, "pk") {
+			report("null", `db tag "null" cannot be combined with "pk"`)
+		}
+	}
+	return diags
+}
+
+// diagnoseTypeModifiers reports size:, precision: and scale: tags that do
+// not apply to the field's column type: size: sets the length of character
+// types, precision: that of numbers and times, scale: that of numbers. An
+// explicit type: or serial column replaces the type they would modify.
+func (a *ParserAdapter) diagnoseTypeModifiers(pField parser.Field, tags []string, size, precision, scale bool, report func(part, format string, args ...any)) {
+	var modifiers []string
+	for _, m := range []struct {
+		key     string
+		present bool
+	}{{"size", size}, {"precision", precision}, {"scale", scale}} {
+		if m.present {
+			modifiers = append(modifiers, m.key)
+		}
+	}
+	if len(modifiers) == 0 {
+		return
+	}
+
+	for _, explicit := range []string{"type", "serial", "bigserial"} {
+		if tag := a.tagWithKey(tags, explicit); tag != "" {
+			for _, key := range modifiers {
+				report(key+":", "db tag %q has no effect with %q", key, explicit)
+			}
+			return
+		}
+	}
+
+	mapping := a.typeMapper.MapFieldType(pField.Type, pField.UnderlyingType)
+	if size && !a.typeMapper.AcceptsSize(mapping) {
+		report("size:", `db tag "size" needs a character column, got %s`, mapping.PostgresType)
+	}
+	if precision && !a.typeMapper.AcceptsPrecision(mapping) {
+		report("precision:", `db tag "precision" needs a numeric or time column, got %s`, mapping.PostgresType)
+	}
+	if scale && !a.typeMapper.AcceptsScale(mapping) {
+		report("scale:", `db tag "scale" needs a numeric column, got %s`, mapping.PostgresType)
+	}
+}
+
+// tagWithPrefix returns the first tag starting with prefix, or "".
+func (a *ParserAdapter) tagWithPrefix(tags []string, prefix string) string {
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, prefix) {
+			return tag
+		}
+	}
+	return ""
+}
+
+// hasIndexTag reports whether tags declare an index.
+func (a *ParserAdapter) hasIndexTag(tags []string) bool {
+	return a.tagWithKey(tags, "index") != "" || a.tagWithKey(tags, "unique_index") != ""
+}
+
+// tagWithKey returns the first tag with the given key, written either bare
+// or as key:value, or "".
+func (a *ParserAdapter) tagWithKey(tags []string, key string) string {
+	for _, tag := range tags {
+		if tag == key || strings.HasPrefix(tag, key+":") {
+			return tag
+		}
+	}
+	return ""
+}
+
+// isIntegerField reports whether the field's type, or the type it is
+// declared with, is an integer type.
+func isIntegerField(pField parser.Field) bool {
+	return integerTypes[pField.Type] || integerTypes[pField.UnderlyingType]
+}
+
+// tagInt returns the integer value of the key:value tag with the given key
+// and whether the tag is present with a valid integer.
+func tagInt(tags []string, key string) (int, bool) {
+	for _, tag := range tags {
+		if value, ok := strings.CutPrefix(tag, key+":"); ok {
+			n, err := strconv.Atoi(value)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// diagnoseForeignKey checks an fk: tag against the two accepted forms,
+// fk:table,column and fk:name,table,column, plus their actions.
+func (a *ParserAdapter) diagnoseForeignKey(tag, spec string, report func(part, format string, args ...any)) {
+	var mainParts []string
+	for _, part := range a.parseFKParts(spec) {
+		part = strings.TrimSpace(part)
+		action, value, ok := strings.Cut(part, ":")
+		if ok && (action == "on_delete" || action == "on_update") {
+			normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(value), "_", " "))
+			if !fkActions[normalized] {
+				report(part, "invalid %s action %q (want CASCADE, RESTRICT, SET_NULL, SET_DEFAULT or NO_ACTION)", action, value)
+			}
+			continue
+		}
+		if part == "" {
+			continue
+		}
+		mainParts = append(mainParts, part)
+	}
+
+	if len(mainParts) != 2 && len(mainParts) != 3 {
+		report(tag, "foreign key %q has %d part(s), want fk:table,column or fk:name,table,column", tag, len(mainParts))
+	}
+}
+
+// tagPartPos returns the position of part within the field's db tag, or the
+// field's position when the tag position is unknown.
+func (a *ParserAdapter) tagPartPos(pField parser.Field, part string) token.Position {
+	if !pField.TagPos.IsValid() {
+		return pField.Pos
+	}
+	pos := pField.TagPos
+	if i := strings.Index(pField.DatabaseTag, part); i >= 0 {
+		pos.Offset += i
+		pos.Column += i
+	}
+	return pos
+}
+
+// suggestTagKey returns the known tag key closest to an unknown one, if any
+// is within two edits.
+func suggestTagKey(key string) string {
+	best, bestDist := "", 3
+	for _, keys := range []map[string]bool{simpleTagKeys, valueTagKeys} {
+		for known := range keys {
+			if d := editDistance(key, known); d < bestDist || (d == bestDist && known < best) {
+				best, bestDist = known, d
+			}
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
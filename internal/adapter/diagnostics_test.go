@@ -0,0 +1,151 @@
+package adapter
+
+import (
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/n0xum/structify/internal/domain/entity"
+	"github.com/n0xum/structify/internal/parser"
+)
+
+func TestDiagnoseField(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	tests := []struct {
+		name        string
+		tag         string
+		goType      string
+		wantMessage string
+	}{
+		{name: "valid simple tags", tag: "pk,unique"},
+		{name: "valid foreign key", tag: "fk:users,id,on_delete:SET_NULL,on_update:cascade"},
+		{name: "valid composite foreign key", tag: "fk:fk_order,orders,id"},
+		{name: "valid embed", tag: "embed,prefix:addr_"},
+		{name: "valid check with commas", tag: "check:coalesce(a, b) > 0"},
+		{name: "valid type override", tag: "type:NUMERIC(12,2),unique"},
+		{name: "valid precision and scale", tag: "precision:12,scale:2"},
+		{name: "invalid size", tag: "size:big", wantMessage: `db tag "size" needs a positive integer, got "big"`},
+		{name: "zero precision", tag: "precision:0", wantMessage: `db tag "precision" needs a positive integer`},
+		{name: "scale without precision", tag: "scale:2", wantMessage: `db tag "scale" requires "precision"`},
+		{name: "scale exceeds precision", tag: "precision:2,scale:4", wantMessage: "scale 4 exceeds precision 2"},
+		{name: "valid size", tag: "size:64", goType: "string"},
+		{name: "valid time precision", tag: "precision:3", goType: "time.Time"},
+		{name: "size on integer", tag: "size:10", wantMessage: `db tag "size" needs a character column, got BIGINT`},
+		{name: "precision on string", tag: "precision:12", goType: "string", wantMessage: `db tag "precision" needs a numeric or time column, got VARCHAR(255)`},
+		{name: "scale on time", tag: "precision:3,scale:1", goType: "time.Time", wantMessage: `db tag "scale" needs a numeric column, got TIMESTAMP`},
+		{name: "size with explicit type", tag: "type:CITEXT,size:64", goType: "string", wantMessage: `db tag "size" has no effect with "type"`},
+		{name: "valid nullability", tag: "null,unique"},
+		{name: "null and notnull", tag: "null,notnull", wantMessage: `"null" cannot be combined with "notnull"`},
+		{name: "nullable primary key", tag: "pk,null", wantMessage: `"null" cannot be combined with "pk"`},
+		{name: "valid identity", tag: "pk,identity"},
+		{name: "identity and serial", tag: "pk,identity,serial", wantMessage: `db tag "serial" cannot be combined with "identity"`},
+		{name: "bigserial on string", tag: "pk,bigserial", goType: "string", wantMessage: `db tag "bigserial" requires an integer field, got string`},
+		{name: "valid generated", tag: "generated:lower(email),unique"},
+		{name: "empty generated", tag: "generated:", wantMessage: `db tag "generated" needs an expression`},
+		{name: "generated with default", tag: "generated:lower(email),default:''", wantMessage: `"generated" cannot be combined with "default"`},
+		{name: "valid index options", tag: "index:idx_tags,using:GIN,where:deleted_at IS NULL,include:name,email,desc,nulls_last,order:2"},
+		{name: "invalid index method", tag: "index,using:rtree", wantMessage: `invalid index method "rtree"`},
+		{name: "index option without index", tag: "where:active", wantMessage: `db tag "where" requires index or unique_index`},
+		{name: "invalid index order", tag: "index:idx_a,order:first", wantMessage: `db tag "order" needs a positive integer`},
+		{name: "unknown key with suggestion", tag: "uniqe", wantMessage: `unknown db tag key "uniqe" (did you mean "unique"?)`},
+		{name: "unknown key", tag: "pk,sparkle", wantMessage: `unknown db tag key "sparkle"`},
+		{name: "simple key with value", tag: "pk:yes", wantMessage: `db tag "pk" takes no value`},
+		{name: "foreign key missing column", tag: "fk:user", wantMessage: "has 1 part(s)"},
+		{name: "foreign key too many parts", tag: "fk:a,b,c,d", wantMessage: "has 4 part(s)"},
+		{name: "invalid on_delete", tag: "fk:users,id,on_delete:EXPLODE", wantMessage: `invalid on_delete action "EXPLODE"`},
+		{name: "pk combined with ignore", tag: "pk,-", wantMessage: `"pk" cannot be combined with "-"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goType := tt.goType
+			if goType == "" {
+				goType = "int64"
+			}
+			diags := adapter.diagnoseField(parser.Field{Name: "Field", Type: goType, DatabaseTag: tt.tag})
+
+			if tt.wantMessage == "" {
+				if len(diags) != 0 {
+					t.Errorf("diagnoseField(%q) = %v, want none", tt.tag, diags)
+				}
+				return
+			}
+			if len(diags) != 1 {
+				t.Fatalf("diagnoseField(%q) returned %d diagnostics, want 1: %v", tt.tag, len(diags), diags)
+			}
+			if diags[0].Severity != entity.SeverityError {
+				t.Errorf("Severity = %v, want error", diags[0].Severity)
+			}
+			if !strings.Contains(diags[0].Message, tt.wantMessage) {
+				t.Errorf("Message = %q, want it to contain %q", diags[0].Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestDiagnoseFieldPosition(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	pField := parser.Field{
+		Name:        "UserID",
+		Type:        "int64",
+		DatabaseTag: "index,fk:user",
+		Pos:         token.Position{Filename: "order.go", Line: 5, Column: 2},
+		TagPos:      token.Position{Filename: "order.go", Line: 5, Column: 20},
+	}
+
+	diags := adapter.diagnoseField(pField)
+	if len(diags) != 1 {
+		t.Fatalf("diagnoseField() returned %d diagnostics, want 1", len(diags))
+	}
+	if got := diags[0].String(); !strings.HasPrefix(got, "order.go:5:26: error: field UserID: ") {
+		t.Errorf("String() = %q, want it to start at order.go:5:26", got)
+	}
+
+	pField.TagPos = token.Position{}
+	diags = adapter.diagnoseField(pField)
+	if got := diags[0].Pos.String(); got != "order.go:5:2" {
+		t.Errorf("Pos without tag position = %s, want order.go:5:2", got)
+	}
+}
+
+func TestToDomainCollectsDiagnostics(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	ent := adapter.ToDomain(&parser.Struct{
+		Name: "Order",
+		Fields: []parser.Field{
+			{Name: "ID", Type: "int64", DatabaseTag: "pk"},
+			{Name: "Code", Type: "string", DatabaseTag: "uniqe"},
+		},
+	})
+
+	if len(ent.Diagnostics) != 1 {
+		t.Fatalf("Diagnostics = %v, want 1 entry", ent.Diagnostics)
+	}
+	if !entity.HasErrors(ent.Diagnostics) {
+		t.Error("HasErrors() = false, want true")
+	}
+}
+
+func TestToDomainReportsUnresolvedEmbeds(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	pos := token.Position{Filename: "account.go", Line: 4, Column: 2}
+	ent := adapter.ToDomain(&parser.Struct{
+		Name:        "Account",
+		Fields:      []parser.Field{{Name: "ID", Type: "int64", DatabaseTag: "pk"}},
+		Diagnostics: []parser.Diagnostic{{Pos: pos, Message: "embedded type audit.Stamps could not be resolved, so its fields are not inlined"}},
+	})
+
+	want := []entity.Diagnostic{{
+		Pos:      pos,
+		Severity: entity.SeverityWarning,
+		Message:  "struct Account: embedded type audit.Stamps could not be resolved, so its fields are not inlined",
+	}}
+	if !reflect.DeepEqual(ent.Diagnostics, want) {
+		t.Errorf("Diagnostics = %v, want %v", ent.Diagnostics, want)
+	}
+}
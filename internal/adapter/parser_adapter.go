@@ -2,9 +2,13 @@ package adapter
 
 import (
 	"fmt"
+	"go/token"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/n0xum/structify/internal/domain/entity"
+	"github.com/n0xum/structify/internal/mapper"
 	"github.com/n0xum/structify/internal/parser"
 	"github.com/n0xum/structify/internal/util"
 )
@@ -12,12 +16,14 @@ import (
 type ParserAdapter struct {
 	patternMatcher *parser.PatternMatcher
 	fieldMapper    *parser.FieldMapper
+	typeMapper     *mapper.Mapper
 }
 
 func NewParserAdapter() *ParserAdapter {
 	return &ParserAdapter{
 		patternMatcher: parser.NewPatternMatcher(),
 		fieldMapper:    parser.NewFieldMapper(),
+		typeMapper:     mapper.NewMapper(),
 	}
 }
 
@@ -28,25 +34,148 @@ func (a *ParserAdapter) ToDomain(pStruct *parser.Struct) *entity.Entity {
 
 	domainFields := make([]entity.Field, 0, len(pStruct.Fields))
 
+	var diagnostics []entity.Diagnostic
 	for _, pField := range pStruct.Fields {
 		domainField := a.toDomainField(pField)
 		domainFields = append(domainFields, domainField)
+		diagnostics = append(diagnostics, a.diagnoseField(pField)...)
+	}
+	// Embeds that could not be inlined leave columns out but do not stop
+	// generation
+	for _, d := range pStruct.Diagnostics {
+		diagnostics = append(diagnostics, entity.Diagnostic{
+			Pos:      d.Pos,
+			Severity: entity.SeverityWarning,
+			Message:  fmt.Sprintf("struct %s: %s", pStruct.Name, d.Message),
+		})
 	}
 
 	domainEntity := &entity.Entity{
-		Name:      pStruct.Name,
-		Fields:    domainFields,
-		TableName: pStruct.TableName,
-		Package:   pStruct.PackageName,
+		Name:        pStruct.Name,
+		Fields:      domainFields,
+		TableName:   pStruct.TableName,
+		Package:     pStruct.PackageName,
+		Schema:      pStruct.PackageSchema,
+		ViewSQL:     pStruct.SQL,
+		Comment:     pStruct.Doc,
+		Diagnostics: diagnostics,
 	}
 
 	if customTable := a.extractCustomTableName(pStruct.Fields); customTable != "" {
-		domainEntity.TableName = customTable
+		a.setTableName(domainEntity, customTable)
 	}
 
+	a.applyDirectives(domainEntity, pStruct.Directives)
+
 	return domainEntity
 }
 
+// setTableName sets the entity's table from a table name that may be
+// qualified with a schema (billing.invoice).
+func (a *ParserAdapter) setTableName(ent *entity.Entity, name string) {
+	schema, table := entity.SplitQualifiedName(name)
+	if schema != "" {
+		ent.Schema = schema
+	}
+	ent.TableName = table
+}
+
+// applyDirectives copies //structify: directives onto the entity and fills
+// the fields of the directives it knows. A table= directive takes precedence
+// over a table: field tag, and schema= over the package's default schema.
+// Malformed partitioning directives are added to the entity's diagnostics,
+// at the position of the directive.
+func (a *ParserAdapter) applyDirectives(ent *entity.Entity, directives []parser.Directive) {
+	reportAt := func(pos token.Position, format string, args ...any) {
+		ent.Diagnostics = append(ent.Diagnostics, entity.Diagnostic{
+			Pos:      pos,
+			Severity: entity.SeverityError,
+			Message:  fmt.Sprintf("struct %s: ", ent.Name) + fmt.Sprintf(format, args...),
+		})
+	}
+	report := func(format string, args ...any) {
+		reportAt(token.Position{}, format, args...)
+	}
+
+	var monthly []parser.Directive
+	for _, d := range directives {
+		ent.Directives = append(ent.Directives, entity.Directive{Key: d.Key, Value: d.Value})
+
+		switch d.Key {
+		case "table":
+			if d.Value != "" {
+				a.setTableName(ent, d.Value)
+			}
+		case "schema":
+			if d.Value != "" {
+				ent.Schema = d.Value
+			}
+		case "unlogged":
+			ent.Unlogged = true
+		case "check":
+			if expr := strings.TrimSpace(d.Value); expr != "" {
+				ent.Checks = append(ent.Checks, expr)
+			}
+		case "exclude":
+			if exclusion := strings.TrimSpace(d.Value); exclusion != "" {
+				ent.Exclusions = append(ent.Exclusions, exclusion)
+			}
+		case "with":
+			for _, param := range strings.Split(d.Value, ",") {
+				if trimmed := strings.TrimSpace(param); trimmed != "" {
+					ent.StorageParams = append(ent.StorageParams, trimmed)
+				}
+			}
+		case "partition_by":
+			if partitioning, ok := parsePartitionBy(d.Value); ok {
+				ent.PartitionBy = partitioning
+			} else {
+				reportAt(d.Pos, "invalid partition_by %q (want range(column), list(column) or hash(column))", d.Value)
+			}
+		case "partition":
+			if partition, ok := parsePartition(d.Value); ok {
+				ent.Partitions = append(ent.Partitions, partition)
+			} else {
+				reportAt(d.Pos, "invalid partition %q (want the partition name followed by its bound)", d.Value)
+			}
+		case "partition_monthly":
+			monthly = append(monthly, d)
+		case "view":
+			switch d.Value {
+			case "":
+				ent.View = true
+			case "materialized":
+				ent.View = true
+				ent.Materialized = true
+			default:
+				reportAt(d.Pos, "invalid view %q (want view or view=materialized)", d.Value)
+			}
+		}
+	}
+
+	if ent.View && strings.TrimSpace(ent.ViewSQL) == "" {
+		report("view needs a //sql: definition or a sql_file= directive")
+	}
+
+	// Monthly partitions are named after the table, which a later table=
+	// directive may still have changed. Their bounds only suit RANGE.
+	for _, d := range monthly {
+		partitions, err := monthlyPartitions(ent.GetTableName(), d.Value)
+		switch {
+		case err != nil:
+			reportAt(d.Pos, "invalid partition_monthly %q (%v)", d.Value, err)
+		case ent.PartitionBy != nil && ent.PartitionBy.Method != "RANGE":
+			reportAt(d.Pos, "partition_monthly needs range partitioning, not %s", strings.ToLower(ent.PartitionBy.Method))
+		default:
+			ent.Partitions = append(ent.Partitions, partitions...)
+		}
+	}
+
+	if len(ent.Partitions) > 0 && ent.PartitionBy == nil {
+		report("partitions need a partition_by directive")
+	}
+}
+
 func (a *ParserAdapter) ToDomainSlice(pStructs []*parser.Struct) []*entity.Entity {
 	if pStructs == nil {
 		return nil
@@ -65,11 +194,24 @@ func (a *ParserAdapter) toDomainField(pField parser.Field) entity.Field {
 	tags := a.parseTags(pField.DatabaseTag)
 
 	domainField := entity.Field{
-		Name:      pField.Name,
-		Type:      pField.Type,
-		IsPrimary: a.hasTag(tags, "pk"),
-		IsUnique:  a.hasTag(tags, "unique"),
-		IsIgnored: a.hasTag(tags, "-"),
+		Name:           pField.Name,
+		Type:           pField.Type,
+		UnderlyingType: pField.UnderlyingType,
+		Column:         pField.Column,
+		Selector:       pField.Selector,
+		Comment:        pField.Doc,
+		Pos:            pField.Pos,
+		IsPrimary:      a.hasTag(tags, "pk"),
+		IsUnique:       a.hasTag(tags, "unique"),
+		IsIgnored:      a.hasTag(tags, "-"),
+		IsNullable:     a.hasTag(tags, "null"),
+		IsNotNull:      a.hasTag(tags, "notnull"),
+	}
+
+	for _, identity := range identityTags {
+		if a.hasTag(tags, identity) {
+			domainField.Identity = identity
+		}
 	}
 
 	// Parse complex tags: check:, default:, index:, enum:, fk:, unique:, etc.
@@ -84,13 +226,13 @@ func (a *ParserAdapter) toDomainField(pField parser.Field) entity.Field {
 			domainField.EnumValues = a.parseEnumValues(enumStr)
 		case tag == "index":
 			// Auto-generate index name
-			domainField.IndexName = a.autoGenerateIndexName(pField.Name)
+			domainField.IndexName = a.autoGenerateIndexName(domainField.ColumnName())
 		case strings.HasPrefix(tag, "index:"):
 			indexName := strings.TrimPrefix(tag, "index:")
 			domainField.IndexName = indexName
 			domainField.IndexGroup = indexName
 		case tag == "unique_index":
-			domainField.IndexName = a.autoGenerateIndexName(pField.Name)
+			domainField.IndexName = a.autoGenerateIndexName(domainField.ColumnName())
 			domainField.IsIndexUnique = true
 		case strings.HasPrefix(tag, "unique_index:"):
 			indexName := strings.TrimPrefix(tag, "unique_index:")
@@ -105,9 +247,48 @@ func (a *ParserAdapter) toDomainField(pField parser.Field) entity.Field {
 		case strings.HasPrefix(tag, "fk:"):
 			// Parse foreign key: fk:table,column[,on_delete:action][,on_update:action]
 			a.parseForeignKey(tag, &domainField)
+		case strings.HasPrefix(tag, "column:"):
+			// Fields inlined with a prefix already carry the prefixed override.
+			if domainField.Column == "" {
+				domainField.Column = strings.TrimPrefix(tag, "column:")
+			}
+		case strings.HasPrefix(tag, "using:"):
+			domainField.IndexMethod = strings.ToLower(strings.TrimPrefix(tag, "using:"))
+		case strings.HasPrefix(tag, "where:"):
+			domainField.IndexWhere = strings.TrimPrefix(tag, "where:")
+		case strings.HasPrefix(tag, "include:"):
+			for _, column := range strings.Split(strings.TrimPrefix(tag, "include:"), ",") {
+				if column = strings.TrimSpace(column); column != "" {
+					domainField.IndexInclude = append(domainField.IndexInclude, column)
+				}
+			}
+		case strings.HasPrefix(tag, "expr:"):
+			domainField.IndexExpr = strings.TrimPrefix(tag, "expr:")
+		case tag == "desc":
+			domainField.IndexDesc = true
+		case tag == "nulls_last":
+			domainField.IndexNullsLast = true
+		case strings.HasPrefix(tag, "order:"):
+			domainField.IndexOrder, _ = strconv.Atoi(strings.TrimPrefix(tag, "order:"))
+		case strings.HasPrefix(tag, "generated:"):
+			domainField.GeneratedExpr = strings.TrimPrefix(tag, "generated:")
+		case strings.HasPrefix(tag, "type:"):
+			domainField.SQLType = strings.TrimPrefix(tag, "type:")
+		case strings.HasPrefix(tag, "size:"):
+			domainField.Size, _ = strconv.Atoi(strings.TrimPrefix(tag, "size:"))
+		case strings.HasPrefix(tag, "precision:"):
+			domainField.Precision, _ = strconv.Atoi(strings.TrimPrefix(tag, "precision:"))
+		case strings.HasPrefix(tag, "scale:"):
+			domainField.Scale, _ = strconv.Atoi(strings.TrimPrefix(tag, "scale:"))
 		}
 	}
 
+	// Without an explicit enum: tag, fall back to the typed constants
+	// declared for the field's type.
+	if len(domainField.EnumValues) == 0 && len(pField.EnumValues) > 0 {
+		domainField.EnumValues = append([]string(nil), pField.EnumValues...)
+	}
+
 	return domainField
 }
 
@@ -127,9 +308,9 @@ func (a *ParserAdapter) parseEnumValues(enumStr string) []string {
 	return values
 }
 
-// autoGenerateIndexName generates an index name based on field name
-func (a *ParserAdapter) autoGenerateIndexName(fieldName string) string {
-	return util.ToSnakeCase(fieldName) + "_idx"
+// autoGenerateIndexName generates an index name based on the column name
+func (a *ParserAdapter) autoGenerateIndexName(columnName string) string {
+	return columnName + "_idx"
 }
 
 // parseForeignKey parses a foreign key tag
@@ -347,7 +528,7 @@ func (a *ParserAdapter) updateTagValueState(state *tagParserState) {
 	currentStr := state.current.String()
 
 	// Enter tag value mode when we see a value tag prefix
-	for _, prefix := range []string{"check:", "default:", "enum:", "fk:"} {
+	for _, prefix := range []string{"check:", "default:", "enum:", "fk:", "type:", "generated:", "where:", "include:", "expr:"} {
 		if strings.HasSuffix(currentStr, prefix) {
 			state.inTagValue = true
 			return
@@ -381,7 +562,7 @@ func (a *ParserAdapter) addFinalPart(state *tagParserState) {
 
 // isSimpleTag checks if the string is a simple tag (no value part)
 func (a *ParserAdapter) isSimpleTag(s string) bool {
-	simpleTags := []string{"pk", "unique", "-", "index", "unique_index"}
+	simpleTags := []string{"pk", "unique", "-", "index", "unique_index", "null", "notnull", "identity", "serial", "bigserial", "desc", "nulls_last"}
 	for _, tag := range simpleTags {
 		if s == tag {
 			return true
@@ -400,13 +581,22 @@ func (a *ParserAdapter) startsWithKnownPrefix(s string) bool {
 		"-":            true,
 		"index":        true,
 		"unique_index": true,
-	}
-	if simpleTags[s] {
+		"null":         true,
+		"notnull":      true,
+		"identity":     true,
+		"serial":       true,
+		"bigserial":    true,
+		"desc":         true,
+		"nulls_last":   true,
+	}
+	// A simple tag may be followed by further tags, e.g. "desc,order:2"
+	first, _, _ := strings.Cut(s, ",")
+	if simpleTags[strings.TrimSpace(first)] {
 		return true
 	}
 
 	// Value tags (prefix match)
-	valuePrefixes := []string{"check:", "default:", "enum:", "fk:"}
+	valuePrefixes := []string{"check:", "default:", "enum:", "fk:", "column:", "type:", "size:", "precision:", "scale:", "generated:", "using:", "where:", "include:", "expr:", "order:"}
 	for _, prefix := range valuePrefixes {
 		if strings.HasPrefix(s, prefix) {
 			return true
@@ -441,13 +631,50 @@ func (a *ParserAdapter) ToMap(structs map[string][]*parser.Struct) map[string][]
 		return nil
 	}
 
+	// Map packages in name order so that nothing derived from all depends
+	// on map iteration
+	pkgNames := make([]string, 0, len(structs))
+	for pkgName := range structs {
+		pkgNames = append(pkgNames, pkgName)
+	}
+	sort.Strings(pkgNames)
+
 	result := make(map[string][]*entity.Entity)
-	for pkgName, pStructs := range structs {
-		result[pkgName] = a.ToDomainSlice(pStructs)
+	var all []*entity.Entity
+	for _, pkgName := range pkgNames {
+		result[pkgName] = a.ToDomainSlice(structs[pkgName])
+		all = append(all, result[pkgName]...)
 	}
+	a.qualifyForeignKeys(all)
 	return result
 }
 
+// qualifyForeignKeys prefixes bare foreign key tables with the schema of
+// the entity they reference, so "invoice" becomes "billing.invoice".
+// References to unknown tables, or to a name several schemas declare, are
+// left as written.
+func (a *ParserAdapter) qualifyForeignKeys(entities []*entity.Entity) {
+	for _, ent := range entities {
+		for i := range ent.Fields {
+			ref := ent.Fields[i].FKReference
+			if ref == nil || strings.Contains(ref.Table, ".") {
+				continue
+			}
+			var target *entity.Entity
+			matches := 0
+			for _, candidate := range entities {
+				if candidate.HasTable(ref.Table) {
+					target = candidate
+					matches++
+				}
+			}
+			if matches == 1 {
+				ref.Table = target.GetQualifiedTableName()
+			}
+		}
+	}
+}
+
 // ToRepositoryInterface converts a parsed interface + entity into a domain RepositoryInterface.
 func (a *ParserAdapter) ToRepositoryInterface(iface *parser.Interface, ent *entity.Entity) *entity.RepositoryInterface {
 	if iface == nil || ent == nil {
@@ -533,6 +760,8 @@ func (a *ParserAdapter) classifyMethod(m parser.Method, ent *entity.Entity) enti
 		return entity.MethodDelete
 	case name == "List" || name == "ListAll":
 		return entity.MethodList
+	case name == "Refresh":
+		return entity.MethodRefresh
 	}
 
 	// 3. Try existing FindBy pattern (for backward compatibility)
@@ -604,19 +833,23 @@ func (a *ParserAdapter) generateSmartQuerySQL(methodName, tableName string, ent
 		fields := ent.GetGenerateableFields()
 		var columns []string
 		for _, f := range fields {
-			columns = append(columns, util.ToSnakeCase(f.Name))
+			columns = append(columns, f.ColumnName())
 		}
 		sb.WriteString("SELECT " + strings.Join(columns, ", ") + " FROM ")
 	}
 
 	sb.WriteString(tableName)
 
+	// The pattern matcher derives columns from field names; map them to the
+	// entity's actual columns so column: overrides apply.
+	columnOf := a.columnLookup(ent)
+
 	// Add WHERE clause if conditions exist
 	if len(matched.Conditions) > 0 {
 		sb.WriteString(" WHERE ")
 		var whereParts []string
 		for _, cond := range matched.Conditions {
-			part := cond.ColumnName + " " + cond.Operator + " $" + fmt.Sprint(cond.ParamIndex)
+			part := columnOf(cond.ColumnName) + " " + cond.Operator + " $" + fmt.Sprint(cond.ParamIndex)
 			if cond.LogicalOp != "" {
 				part += " " + cond.LogicalOp
 			}
@@ -627,7 +860,8 @@ func (a *ParserAdapter) generateSmartQuerySQL(methodName, tableName string, ent
 
 	// Add ORDER BY clause
 	if matched.OrderBy != "" {
-		sb.WriteString(" ORDER BY " + matched.OrderBy)
+		column, direction, _ := strings.Cut(matched.OrderBy, " ")
+		sb.WriteString(" ORDER BY " + strings.TrimSpace(columnOf(column)+" "+direction))
 	}
 
 	// Add LIMIT clause
@@ -648,6 +882,21 @@ func (a *ParserAdapter) generateSmartQuerySQL(methodName, tableName string, ent
 	return sb.String(), nil
 }
 
+// columnLookup returns a function that maps the snake_case form of a field
+// name to the field's column, leaving unknown names unchanged.
+func (a *ParserAdapter) columnLookup(ent *entity.Entity) func(string) string {
+	columns := make(map[string]string, len(ent.Fields))
+	for _, f := range ent.Fields {
+		columns[util.ToSnakeCase(f.Name)] = f.ColumnName()
+	}
+	return func(name string) string {
+		if column, ok := columns[name]; ok {
+			return column
+		}
+		return name
+	}
+}
+
 // ToInterfaceMap converts parsed interfaces to domain format.
 func (a *ParserAdapter) ToInterfaceMap(interfaces map[string][]*parser.Interface) map[string][]*entity.RepositoryInterface {
 	if interfaces == nil {
@@ -1,6 +1,9 @@
 package adapter
 
 import (
+	"go/token"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/n0xum/structify/internal/domain/entity"
@@ -347,6 +350,7 @@ func TestParserAdapterEnumConstraint(t *testing.T) {
 	tests := []struct {
 		name         string
 		tag          string
+		typedVals    []string
 		wantEnumVals []string
 	}{
 		{
@@ -364,6 +368,17 @@ func TestParserAdapterEnumConstraint(t *testing.T) {
 			tag:          "enum:low, medium, high",
 			wantEnumVals: []string{"low", "medium", "high"},
 		},
+		{
+			name:         "typed constants",
+			typedVals:    []string{"pending", "active"},
+			wantEnumVals: []string{"pending", "active"},
+		},
+		{
+			name:         "explicit enum overrides typed constants",
+			tag:          "enum:on,off",
+			typedVals:    []string{"pending", "active"},
+			wantEnumVals: []string{"on", "off"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -372,6 +387,7 @@ func TestParserAdapterEnumConstraint(t *testing.T) {
 				Name:        "TestField",
 				Type:        "string",
 				DatabaseTag: tt.tag,
+				EnumValues:  tt.typedVals,
 			}
 
 			domainField := adapter.toDomainField(pField)
@@ -738,6 +754,8 @@ func TestStartsWithKnownPrefix(t *testing.T) {
 		{"unknown tag", "unknown", false},
 		{"empty string", "", false},
 		{"partial match", "pkcustom", false}, // "pk" is an exact tag, not a prefix
+		{"simple tag followed by tags", "unique,index", true},
+		{"partial match followed by tags", "pkcustom,index", false},
 	}
 
 	for _, tt := range tests {
@@ -1102,6 +1120,7 @@ func TestClassifyMethod_ListAndListAll(t *testing.T) {
 	}{
 		{"List method", "List", entity.MethodList},
 		{"ListAll method", "ListAll", entity.MethodList},
+		{"Refresh method", "Refresh", entity.MethodRefresh},
 		{"Get method", "Get", entity.MethodGetByID},
 	}
 
@@ -1262,3 +1281,558 @@ func TestProcessSmartQueryMethod_NoMatch(t *testing.T) {
 		t.Errorf("GeneratedSQL should be empty for unmatched method, got %q", rm.GeneratedSQL)
 	}
 }
+
+func TestParserAdapterDirectives(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	pStruct := &parser.Struct{
+		Name:      "ShopOrder",
+		TableName: "shop_order",
+		Fields: []parser.Field{
+			{Name: "ID", Type: "int64", DatabaseTag: "pk,table:legacy_orders"},
+		},
+		Directives: []parser.Directive{
+			{Key: "table", Value: "orders"},
+			{Key: "schema", Value: "shop"},
+			{Key: "unlogged"},
+			{Key: "with", Value: "fillfactor=70, autovacuum_enabled=false"},
+			{Key: "audit", Value: "full"},
+			{Key: "check", Value: "start_at < end_at"},
+			{Key: "exclude", Value: "USING gist (room_id WITH =, during WITH &&)"},
+			{Key: "check", Value: " "},
+		},
+	}
+
+	ent := adapter.ToDomain(pStruct)
+
+	if ent.TableName != "orders" {
+		t.Errorf("TableName = %v, want orders (directive wins over field tag)", ent.TableName)
+	}
+	if ent.Schema != "shop" {
+		t.Errorf("Schema = %v, want shop", ent.Schema)
+	}
+	if !ent.Unlogged {
+		t.Error("Unlogged = false, want true")
+	}
+	if len(ent.StorageParams) != 2 || ent.StorageParams[1] != "autovacuum_enabled=false" {
+		t.Errorf("StorageParams = %v, want [fillfactor=70 autovacuum_enabled=false]", ent.StorageParams)
+	}
+	if len(ent.Directives) != 8 {
+		t.Errorf("Directives length = %d, want 8", len(ent.Directives))
+	}
+	if len(ent.Checks) != 1 || ent.Checks[0] != "start_at < end_at" {
+		t.Errorf("Checks = %q, want [start_at < end_at]", ent.Checks)
+	}
+	if len(ent.Exclusions) != 1 || ent.Exclusions[0] != "USING gist (room_id WITH =, during WITH &&)" {
+		t.Errorf("Exclusions = %q, want the exclude directive", ent.Exclusions)
+	}
+	if value, ok := ent.Directive("audit"); !ok || value != "full" {
+		t.Errorf("Directive(audit) = (%q, %v), want (full, true)", value, ok)
+	}
+}
+
+func TestParserAdapterSchema(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	structs := map[string][]*parser.Struct{
+		"billing": {
+			{
+				Name:          "Invoice",
+				TableName:     "invoice",
+				PackageSchema: "billing",
+				Fields: []parser.Field{
+					{Name: "ID", Type: "int64", DatabaseTag: "pk"},
+					{Name: "CustomerID", Type: "int64", DatabaseTag: "fk:customer,id"},
+				},
+			},
+			{
+				Name:          "Payment",
+				TableName:     "payment",
+				PackageSchema: "billing",
+				Fields: []parser.Field{
+					{Name: "ID", Type: "int64", DatabaseTag: "pk,table:ledger.payments"},
+					{Name: "InvoiceID", Type: "int64", DatabaseTag: "fk:invoice,id"},
+				},
+			},
+		},
+		"crm": {
+			{
+				Name:       "Customer",
+				TableName:  "customer",
+				Directives: []parser.Directive{{Key: "schema", Value: "crm"}},
+				Fields: []parser.Field{
+					{Name: "ID", Type: "int64", DatabaseTag: "pk"},
+				},
+			},
+		},
+	}
+
+	result := adapter.ToMap(structs)
+	invoice, payment, customer := result["billing"][0], result["billing"][1], result["crm"][0]
+
+	if got := invoice.GetQualifiedTableName(); got != "billing.invoice" {
+		t.Errorf("Invoice table = %v, want billing.invoice (package default)", got)
+	}
+	if got := payment.GetQualifiedTableName(); got != "ledger.payments" {
+		t.Errorf("Payment table = %v, want ledger.payments (table: tag)", got)
+	}
+	if got := customer.GetQualifiedTableName(); got != "crm.customer" {
+		t.Errorf("Customer table = %v, want crm.customer (schema directive)", got)
+	}
+	if got := invoice.Fields[1].FKReference.Table; got != "crm.customer" {
+		t.Errorf("Invoice.CustomerID references %v, want crm.customer", got)
+	}
+	if got := payment.Fields[1].FKReference.Table; got != "billing.invoice" {
+		t.Errorf("Payment.InvoiceID references %v, want billing.invoice", got)
+	}
+}
+
+func TestParserAdapterPartitioning(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	pStruct := &parser.Struct{
+		Name:      "Event",
+		TableName: "event",
+		Fields: []parser.Field{
+			{Name: "ID", Type: "int64", DatabaseTag: "pk"},
+			{Name: "CreatedAt", Type: "time.Time", DatabaseTag: "pk"},
+		},
+		Directives: []parser.Directive{
+			{Key: "partition_by", Value: "range(created_at)"},
+			{Key: "partition_monthly", Value: "2026-11,3"},
+			{Key: "partition", Value: "events_old FROM (MINVALUE) TO ('2026-11-01')"},
+			{Key: "table", Value: "events"},
+		},
+	}
+
+	ent := adapter.ToDomain(pStruct)
+
+	if len(ent.Diagnostics) != 0 {
+		t.Fatalf("Diagnostics = %v, want none", ent.Diagnostics)
+	}
+	wantBy := &entity.Partitioning{Method: "RANGE", Columns: []string{"created_at"}}
+	if !reflect.DeepEqual(ent.PartitionBy, wantBy) {
+		t.Errorf("PartitionBy = %+v, want %+v", ent.PartitionBy, wantBy)
+	}
+	wantPartitions := []entity.Partition{
+		{Name: "events_old", Bound: "FOR VALUES FROM (MINVALUE) TO ('2026-11-01')"},
+		{Name: "events_2026_11", Bound: "FOR VALUES FROM ('2026-11-01') TO ('2026-12-01')"},
+		{Name: "events_2026_12", Bound: "FOR VALUES FROM ('2026-12-01') TO ('2027-01-01')"},
+		{Name: "events_2027_01", Bound: "FOR VALUES FROM ('2027-01-01') TO ('2027-02-01')"},
+	}
+	if !reflect.DeepEqual(ent.Partitions, wantPartitions) {
+		t.Errorf("Partitions = %+v, want %+v", ent.Partitions, wantPartitions)
+	}
+}
+
+func TestParserAdapterPartitioningDiagnostics(t *testing.T) {
+	monthlyPos := token.Position{Filename: "event.go", Line: 4, Column: 1}
+
+	tests := []struct {
+		name       string
+		directives []parser.Directive
+		want       string
+	}{
+		{
+			name:       "unknown method",
+			directives: []parser.Directive{{Key: "partition_by", Value: "interval(created_at)"}},
+			want:       `invalid partition_by "interval(created_at)"`,
+		},
+		{
+			name:       "missing key",
+			directives: []parser.Directive{{Key: "partition_by", Value: "range()"}},
+			want:       `invalid partition_by "range()"`,
+		},
+		{
+			name: "partition without bound",
+			directives: []parser.Directive{
+				{Key: "partition_by", Value: "list(region)"},
+				{Key: "partition", Value: "events_eu"},
+			},
+			want: `invalid partition "events_eu"`,
+		},
+		{
+			name: "malformed monthly",
+			directives: []parser.Directive{
+				{Key: "partition_by", Value: "range(created_at)"},
+				{Key: "partition_monthly", Value: "2026-13,2"},
+			},
+			want: `invalid partition_monthly "2026-13,2"`,
+		},
+		{
+			name:       "partitions without partition_by",
+			directives: []parser.Directive{{Key: "partition", Value: "events_other DEFAULT"}},
+			want:       "partitions need a partition_by directive",
+		},
+		{
+			name: "no monthly partitions",
+			directives: []parser.Directive{
+				{Key: "partition_by", Value: "range(created_at)"},
+				{Key: "partition_monthly", Value: "2026-11,0", Pos: monthlyPos},
+			},
+			want: `invalid partition_monthly "2026-11,0" (count 0 is out of range (want 1 to 120))`,
+		},
+		{
+			name: "negative monthly count",
+			directives: []parser.Directive{
+				{Key: "partition_by", Value: "range(created_at)"},
+				{Key: "partition_monthly", Value: "2026-11,-3", Pos: monthlyPos},
+			},
+			want: "count -3 is out of range",
+		},
+		{
+			name: "too many monthly partitions",
+			directives: []parser.Directive{
+				{Key: "partition_by", Value: "range(created_at)"},
+				{Key: "partition_monthly", Value: "2026-11,100000", Pos: monthlyPos},
+			},
+			want: "count 100000 is out of range",
+		},
+		{
+			name: "monthly partitions of a list",
+			directives: []parser.Directive{
+				{Key: "partition_by", Value: "list(region)"},
+				{Key: "partition_monthly", Value: "2026-11,3", Pos: monthlyPos},
+			},
+			want: "partition_monthly needs range partitioning, not list",
+		},
+		{
+			name: "monthly partitions of a hash",
+			directives: []parser.Directive{
+				{Key: "partition_by", Value: "hash(id)"},
+				{Key: "partition_monthly", Value: "2026-11,3", Pos: monthlyPos},
+			},
+			want: "partition_monthly needs range partitioning, not hash",
+		},
+	}
+
+	adapter := NewParserAdapter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ent := adapter.ToDomain(&parser.Struct{
+				Name:       "Event",
+				Fields:     []parser.Field{{Name: "ID", Type: "int64", DatabaseTag: "pk"}},
+				Directives: tt.directives,
+			})
+			if len(ent.Diagnostics) != 1 || !strings.Contains(ent.Diagnostics[0].Message, tt.want) {
+				t.Fatalf("Diagnostics = %v, want one containing %q", ent.Diagnostics, tt.want)
+			}
+			// Directive problems are reported where the directive is
+			last := tt.directives[len(tt.directives)-1]
+			if got := ent.Diagnostics[0].Pos; got != last.Pos {
+				t.Errorf("Diagnostics[0].Pos = %v, want %v", got, last.Pos)
+			}
+		})
+	}
+}
+
+func TestParserAdapterViews(t *testing.T) {
+	tests := []struct {
+		name             string
+		directives       []parser.Directive
+		sql              string
+		wantView         bool
+		wantMaterialized bool
+		wantDiagnostic   string
+	}{
+		{
+			name:       "table",
+			directives: nil,
+		},
+		{
+			name:       "view",
+			directives: []parser.Directive{{Key: "view"}},
+			sql:        "SELECT id FROM users",
+			wantView:   true,
+		},
+		{
+			name:             "materialized view",
+			directives:       []parser.Directive{{Key: "view", Value: "materialized"}},
+			sql:              "SELECT id FROM users",
+			wantView:         true,
+			wantMaterialized: true,
+		},
+		{
+			name:           "view without definition",
+			directives:     []parser.Directive{{Key: "view"}},
+			wantView:       true,
+			wantDiagnostic: "view needs a //sql: definition",
+		},
+		{
+			name:           "unknown view kind",
+			directives:     []parser.Directive{{Key: "view", Value: "recursive"}},
+			sql:            "SELECT 1",
+			wantDiagnostic: `invalid view "recursive"`,
+		},
+	}
+
+	adapter := NewParserAdapter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ent := adapter.ToDomain(&parser.Struct{
+				Name:       "ActiveUser",
+				Fields:     []parser.Field{{Name: "ID", Type: "int64"}},
+				Directives: tt.directives,
+				SQL:        tt.sql,
+			})
+
+			if ent.View != tt.wantView || ent.Materialized != tt.wantMaterialized {
+				t.Errorf("View, Materialized = %v, %v, want %v, %v", ent.View, ent.Materialized, tt.wantView, tt.wantMaterialized)
+			}
+			if ent.ViewSQL != tt.sql {
+				t.Errorf("ViewSQL = %q, want %q", ent.ViewSQL, tt.sql)
+			}
+			if tt.wantDiagnostic == "" {
+				if len(ent.Diagnostics) != 0 {
+					t.Errorf("Diagnostics = %v, want none", ent.Diagnostics)
+				}
+			} else if len(ent.Diagnostics) != 1 || !strings.Contains(ent.Diagnostics[0].Message, tt.wantDiagnostic) {
+				t.Errorf("Diagnostics = %v, want one containing %q", ent.Diagnostics, tt.wantDiagnostic)
+			}
+		})
+	}
+}
+
+func TestParserAdapterColumnOverride(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	tests := []struct {
+		name          string
+		pField        parser.Field
+		wantColumn    string
+		wantIndexName string
+	}{
+		{
+			name:       "column override",
+			pField:     parser.Field{Name: "UserID", Type: "int64", DatabaseTag: "pk,column:userid"},
+			wantColumn: "userid",
+		},
+		{
+			name:          "auto index named after column",
+			pField:        parser.Field{Name: "EMailAddr", Type: "string", DatabaseTag: "column:email,index"},
+			wantColumn:    "email",
+			wantIndexName: "email_idx",
+		},
+		{
+			name:       "column after check expression",
+			pField:     parser.Field{Name: "Qty", Type: "int", DatabaseTag: "check:qty > 0,column:qty"},
+			wantColumn: "qty",
+		},
+		{
+			name:       "inlined field keeps prefixed column",
+			pField:     parser.Field{Name: "HomeStreet", Type: "string", DatabaseTag: "column:str", Column: "home_str"},
+			wantColumn: "home_str",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := adapter.toDomainField(tt.pField)
+			if got := f.ColumnName(); got != tt.wantColumn {
+				t.Errorf("ColumnName() = %v, want %v", got, tt.wantColumn)
+			}
+			if f.IndexName != tt.wantIndexName {
+				t.Errorf("IndexName = %v, want %v", f.IndexName, tt.wantIndexName)
+			}
+			if diags := adapter.diagnoseField(tt.pField); len(diags) != 0 {
+				t.Errorf("diagnoseField() = %v, want none", diags)
+			}
+		})
+	}
+}
+
+func TestSmartQuery_ColumnOverride(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	ent := &entity.Entity{
+		Name: "User",
+		Fields: []entity.Field{
+			{Name: "UserID", Type: "int64", IsPrimary: true, Column: "userid"},
+			{Name: "Email", Type: "string", Column: "mail"},
+			{Name: "CreatedAt", Type: "time.Time", Column: "created"},
+		},
+	}
+
+	m := parser.Method{Name: "ListUsersByEmailOrderByCreatedAtDesc"}
+	rm := entity.RepositoryMethod{Name: m.Name}
+	adapter.processSmartQueryMethod(&rm, m, ent)
+
+	want := `SELECT userid, mail, created FROM "user" WHERE mail = $1 ORDER BY created DESC`
+	if rm.GeneratedSQL != want {
+		t.Errorf("GeneratedSQL = %s, want %s", rm.GeneratedSQL, want)
+	}
+}
+
+func TestParserAdapterTypeOverrides(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	tests := []struct {
+		name          string
+		tag           string
+		wantSQLType   string
+		wantSize      int
+		wantPrecision int
+		wantScale     int
+		wantUnique    bool
+	}{
+		{name: "type with comma", tag: "type:NUMERIC(12,2),unique", wantSQLType: "NUMERIC(12,2)", wantUnique: true},
+		{name: "size", tag: "size:64", wantSize: 64},
+		{name: "precision and scale", tag: "precision:12,scale:2", wantPrecision: 12, wantScale: 2},
+		{name: "after check expression", tag: "check:amount >= 0,precision:10,scale:4", wantPrecision: 10, wantScale: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := adapter.toDomainField(parser.Field{Name: "Amount", Type: "float64", DatabaseTag: tt.tag})
+			if f.SQLType != tt.wantSQLType {
+				t.Errorf("SQLType = %v, want %v", f.SQLType, tt.wantSQLType)
+			}
+			if f.Size != tt.wantSize {
+				t.Errorf("Size = %v, want %v", f.Size, tt.wantSize)
+			}
+			if f.Precision != tt.wantPrecision || f.Scale != tt.wantScale {
+				t.Errorf("Precision, Scale = %v, %v, want %v, %v", f.Precision, f.Scale, tt.wantPrecision, tt.wantScale)
+			}
+			if f.IsUnique != tt.wantUnique {
+				t.Errorf("IsUnique = %v, want %v", f.IsUnique, tt.wantUnique)
+			}
+		})
+	}
+}
+
+func TestParserAdapterNullability(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	tests := []struct {
+		tag          string
+		wantNullable bool
+		wantNotNull  bool
+	}{
+		{tag: "", wantNullable: false, wantNotNull: false},
+		{tag: "null", wantNullable: true},
+		{tag: "notnull,unique", wantNotNull: true},
+		{tag: "check:length(nick) > 2,null", wantNullable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			f := adapter.toDomainField(parser.Field{Name: "Nick", Type: "string", DatabaseTag: tt.tag})
+			if f.IsNullable != tt.wantNullable {
+				t.Errorf("IsNullable = %v, want %v", f.IsNullable, tt.wantNullable)
+			}
+			if f.IsNotNull != tt.wantNotNull {
+				t.Errorf("IsNotNull = %v, want %v", f.IsNotNull, tt.wantNotNull)
+			}
+		})
+	}
+}
+
+func TestParserAdapterIdentity(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{tag: "pk", want: ""},
+		{tag: "pk,identity", want: "identity"},
+		{tag: "serial,pk", want: "serial"},
+		{tag: "pk,bigserial", want: "bigserial"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			f := adapter.toDomainField(parser.Field{Name: "ID", Type: "int64", DatabaseTag: tt.tag})
+			if f.Identity != tt.want {
+				t.Errorf("Identity = %q, want %q", f.Identity, tt.want)
+			}
+			if got := f.IsGeneratedByDatabase(); got != (tt.want != "") {
+				t.Errorf("IsGeneratedByDatabase() = %v, want %v", got, tt.want != "")
+			}
+		})
+	}
+}
+
+func TestParserAdapterGeneratedColumn(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	tests := []struct {
+		tag        string
+		wantExpr   string
+		wantUnique bool
+	}{
+		{tag: "generated:lower(email)", wantExpr: "lower(email)"},
+		{tag: "generated:coalesce(nick, name),unique", wantExpr: "coalesce(nick, name)", wantUnique: true},
+		{tag: "index,generated:lower(title)", wantExpr: "lower(title)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			f := adapter.toDomainField(parser.Field{Name: "Slug", Type: "string", DatabaseTag: tt.tag})
+			if f.GeneratedExpr != tt.wantExpr {
+				t.Errorf("GeneratedExpr = %q, want %q", f.GeneratedExpr, tt.wantExpr)
+			}
+			if f.IsUnique != tt.wantUnique {
+				t.Errorf("IsUnique = %v, want %v", f.IsUnique, tt.wantUnique)
+			}
+			if !f.IsGeneratedByDatabase() {
+				t.Error("IsGeneratedByDatabase() = false, want true")
+			}
+		})
+	}
+}
+
+func TestParserAdapterComments(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	ent := adapter.ToDomain(&parser.Struct{
+		Name: "Customer",
+		Doc:  "Customer is somebody who buys things.",
+		Fields: []parser.Field{
+			{Name: "ID", Type: "int64", DatabaseTag: "pk", Doc: "The customer's number."},
+			{Name: "Note", Type: "string"},
+		},
+	})
+
+	if ent.Comment != "Customer is somebody who buys things." {
+		t.Errorf("Comment = %q, want the struct doc", ent.Comment)
+	}
+	if got := ent.Fields[0].Comment; got != "The customer's number." {
+		t.Errorf("ID Comment = %q, want the field doc", got)
+	}
+	if got := ent.Fields[1].Comment; got != "" {
+		t.Errorf("Note Comment = %q, want empty", got)
+	}
+}
+
+func TestParserAdapterIndexOptions(t *testing.T) {
+	adapter := NewParserAdapter()
+
+	f := adapter.toDomainField(parser.Field{
+		Name:        "Email",
+		Type:        "string",
+		DatabaseTag: "unique_index:uq_email,expr:lower(email),using:BTREE,where:deleted_at IS NULL,include:name,created_at,desc,nulls_last,order:2",
+	})
+
+	if f.IndexName != "uq_email" || !f.IsIndexUnique {
+		t.Errorf("IndexName, IsIndexUnique = %q, %v, want uq_email, true", f.IndexName, f.IsIndexUnique)
+	}
+	if f.IndexExpr != "lower(email)" {
+		t.Errorf("IndexExpr = %q, want lower(email)", f.IndexExpr)
+	}
+	if f.IndexMethod != "btree" {
+		t.Errorf("IndexMethod = %q, want btree", f.IndexMethod)
+	}
+	if f.IndexWhere != "deleted_at IS NULL" {
+		t.Errorf("IndexWhere = %q, want deleted_at IS NULL", f.IndexWhere)
+	}
+	if want := []string{"name", "created_at"}; !reflect.DeepEqual(f.IndexInclude, want) {
+		t.Errorf("IndexInclude = %v, want %v", f.IndexInclude, want)
+	}
+	if !f.IndexDesc || !f.IndexNullsLast {
+		t.Errorf("IndexDesc, IndexNullsLast = %v, %v, want true, true", f.IndexDesc, f.IndexNullsLast)
+	}
+	if f.IndexOrder != 2 {
+		t.Errorf("IndexOrder = %d, want 2", f.IndexOrder)
+	}
+}
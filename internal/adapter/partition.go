@@ -0,0 +1,99 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/n0xum/structify/internal/domain/entity"
+)
+
+// partitionMethods are the accepted partition_by methods.
+var partitionMethods = map[string]bool{
+	"range": true,
+	"list":  true,
+	"hash":  true,
+}
+
+// parsePartitionBy parses a partition_by directive such as range(created_at)
+// or "list(region, kind)".
+func parsePartitionBy(value string) (*entity.Partitioning, bool) {
+	value = strings.TrimSpace(value)
+	open := strings.Index(value, "(")
+	if open <= 0 || !strings.HasSuffix(value, ")") {
+		return nil, false
+	}
+	method := strings.ToLower(strings.TrimSpace(value[:open]))
+	if !partitionMethods[method] {
+		return nil, false
+	}
+
+	var columns []string
+	for _, column := range strings.Split(value[open+1:len(value)-1], ",") {
+		column = strings.TrimSpace(column)
+		if column == "" {
+			return nil, false
+		}
+		columns = append(columns, column)
+	}
+	return &entity.Partitioning{Method: strings.ToUpper(method), Columns: columns}, true
+}
+
+// parsePartition parses a partition directive: the child table's name
+// followed by its bound, e.g. events_eu IN ('de', 'fr'),
+// events_old FROM (MINVALUE) TO ('2026-01-01') or events_other DEFAULT.
+func parsePartition(value string) (entity.Partition, bool) {
+	name, bound, _ := strings.Cut(strings.TrimSpace(value), " ")
+	bound = strings.TrimSpace(bound)
+	if name == "" || bound == "" {
+		return entity.Partition{}, false
+	}
+
+	upper := strings.ToUpper(bound)
+	switch {
+	case upper == "DEFAULT":
+		bound = "DEFAULT"
+	case !strings.HasPrefix(upper, "FOR VALUES "):
+		bound = "FOR VALUES " + bound
+	}
+	return entity.Partition{Name: name, Bound: bound}, true
+}
+
+// maxMonthlyPartitions caps the count of a partition_monthly directive at
+// ten years of partitions.
+const maxMonthlyPartitions = 120
+
+var errMonthlyFormat = errors.New("want YYYY-MM,count")
+
+// monthlyPartitions parses a partition_monthly directive "YYYY-MM,count"
+// into count range partitions of table, one per month, named table_YYYY_MM.
+func monthlyPartitions(table, value string) ([]entity.Partition, error) {
+	startText, countText, ok := strings.Cut(value, ",")
+	if !ok {
+		return nil, errMonthlyFormat
+	}
+	start, err := time.Parse("2006-01", strings.TrimSpace(startText))
+	if err != nil {
+		return nil, errMonthlyFormat
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(countText))
+	if err != nil {
+		return nil, errMonthlyFormat
+	}
+	if count < 1 || count > maxMonthlyPartitions {
+		return nil, fmt.Errorf("count %d is out of range (want 1 to %d)", count, maxMonthlyPartitions)
+	}
+
+	partitions := make([]entity.Partition, count)
+	for i := range partitions {
+		from := start.AddDate(0, i, 0)
+		to := from.AddDate(0, 1, 0)
+		partitions[i] = entity.Partition{
+			Name:  table + "_" + from.Format("2006_01"),
+			Bound: fmt.Sprintf("FOR VALUES FROM ('%s') TO ('%s')", from.Format("2006-01-02"), to.Format("2006-01-02")),
+		}
+	}
+	return partitions, nil
+}
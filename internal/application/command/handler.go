@@ -2,6 +2,8 @@ package command
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/n0xum/structify/internal/domain/entity"
 	"github.com/n0xum/structify/internal/domain/validator"
@@ -12,9 +14,29 @@ type Handler struct {
 }
 
 type Generator interface {
-	GenerateSchema(ctx context.Context, entities []*entity.Entity) (string, error)
+	GenerateSchema(ctx context.Context, entities []*entity.Entity, opts SchemaOptions) (string, error)
 	GenerateCode(ctx context.Context, packageName string, entities []*entity.Entity) (string, error)
 	GenerateRepository(ctx context.Context, packageName string, ent *entity.Entity, repo *entity.RepositoryInterface) (string, error)
+	GenerateMigration(ctx context.Context, from, to []*entity.Entity) (*entity.Migration, error)
+}
+
+// SchemaMode selects the statements GenerateSchema writes.
+type SchemaMode int
+
+const (
+	// ModeCreate creates the schema. It is the default.
+	ModeCreate SchemaMode = iota
+	// ModeDrop drops the tables and views, dependents first.
+	ModeDrop
+	// ModeReset drops the tables and views and creates them again.
+	ModeReset
+)
+
+// SchemaOptions control the DDL the generator writes for a schema.
+type SchemaOptions struct {
+	Mode SchemaMode
+	// IfNotExists makes the CREATE statements safe to re-run.
+	IfNotExists bool
 }
 
 func NewHandler(generator Generator) *Handler {
@@ -26,13 +48,33 @@ func NewHandler(generator Generator) *Handler {
 type GenerateSchemaCommand struct {
 	PackageName string
 	Entities    []*entity.Entity
+	// IfNotExists makes the generated schema safe to apply more than once.
+	IfNotExists bool
+	// Drop generates DROP TABLE IF EXISTS ... CASCADE statements instead
+	// of the schema.
+	Drop bool
+	// Reset generates the DROP statements followed by the schema; it takes
+	// precedence over Drop.
+	Reset bool
+}
+
+// schemaOptions returns the generator options the command asks for.
+func (cmd *GenerateSchemaCommand) schemaOptions() SchemaOptions {
+	opts := SchemaOptions{IfNotExists: cmd.IfNotExists}
+	switch {
+	case cmd.Reset:
+		opts.Mode = ModeReset
+	case cmd.Drop:
+		opts.Mode = ModeDrop
+	}
+	return opts
 }
 
 func (h *Handler) GenerateSchema(ctx context.Context, cmd *GenerateSchemaCommand) (string, error) {
 	if err := h.validateEntities(cmd.Entities); err != nil {
 		return "", err
 	}
-	return h.generator.GenerateSchema(ctx, cmd.Entities)
+	return h.generator.GenerateSchema(ctx, cmd.Entities, cmd.schemaOptions())
 }
 
 func (h *Handler) GenerateCode(ctx context.Context, cmd *GenerateSchemaCommand) (string, error) {
@@ -55,13 +97,38 @@ func (h *Handler) GenerateRepository(ctx context.Context, cmd *GenerateRepoComma
 	return h.generator.GenerateRepository(ctx, cmd.PackageName, cmd.Entity, cmd.Interface)
 }
 
+// validateEntities validates every entity on its own and then the model as
+// a whole, returning all problems at once.
 func (h *Handler) validateEntities(entities []*entity.Entity) error {
+	var errs []error
 	for _, ent := range entities {
 		if _, err := validator.NewValidatedEntity(ent); err != nil {
-			return err
+			errs = append(errs, fmt.Errorf("%s: %w", ent.Name, err))
 		}
 	}
-	return nil
+	if err := validator.ValidateModel(entities); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// DiffCommand compares two versions of a model: From is the schema the
+// database has, To the one it should get.
+type DiffCommand struct {
+	From []*entity.Entity
+	To   []*entity.Entity
+}
+
+// Diff validates both versions of the model and returns the migration
+// between them.
+func (h *Handler) Diff(ctx context.Context, cmd *DiffCommand) (*entity.Migration, error) {
+	if err := h.validateEntities(cmd.From); err != nil {
+		return nil, fmt.Errorf("from: %w", err)
+	}
+	if err := h.validateEntities(cmd.To); err != nil {
+		return nil, fmt.Errorf("to: %w", err)
+	}
+	return h.generator.GenerateMigration(ctx, cmd.From, cmd.To)
 }
 
 type ValidateCommand struct {
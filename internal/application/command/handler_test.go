@@ -6,16 +6,20 @@ import (
 	"testing"
 
 	"github.com/n0xum/structify/internal/domain/entity"
+	"github.com/n0xum/structify/internal/domain/validator"
 )
 
 type mockGenerator struct {
-	schemaResult string
-	codeResult   string
-	schemaError  error
-	codeError    error
+	schemaResult  string
+	codeResult    string
+	schemaError   error
+	codeError     error
+	schemaOptions SchemaOptions
+	migration     *entity.Migration
 }
 
-func (m *mockGenerator) GenerateSchema(ctx context.Context, entities []*entity.Entity) (string, error) {
+func (m *mockGenerator) GenerateSchema(ctx context.Context, entities []*entity.Entity, opts SchemaOptions) (string, error) {
+	m.schemaOptions = opts
 	return m.schemaResult, m.schemaError
 }
 
@@ -27,6 +31,10 @@ func (m *mockGenerator) GenerateRepository(ctx context.Context, packageName stri
 	return m.codeResult, m.codeError
 }
 
+func (m *mockGenerator) GenerateMigration(ctx context.Context, from, to []*entity.Entity) (*entity.Migration, error) {
+	return m.migration, m.schemaError
+}
+
 func TestHandlerGenerateSchema(t *testing.T) {
 	t.Run("valid entities", func(t *testing.T) {
 		gen := &mockGenerator{schemaResult: "CREATE TABLE..."}
@@ -117,6 +125,100 @@ func TestHandlerValidate(t *testing.T) {
 			t.Error("Validate() expected error for invalid entity")
 		}
 	})
+
+	t.Run("model errors joined", func(t *testing.T) {
+		gen := &mockGenerator{}
+		handler := NewHandler(gen)
+
+		entities := []*entity.Entity{
+			{Name: "", Fields: []entity.Field{}},
+			{Name: "Order", Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "UserID", Type: "int64", FKReference: &entity.FKReference{Table: "user", Column: "id"}},
+			}},
+		}
+
+		cmd := &ValidateCommand{Entities: entities}
+		err := handler.Validate(context.Background(), cmd)
+
+		if !errors.Is(err, entity.ErrEntityNameRequired) {
+			t.Errorf("Validate() error = %v, want it to include ErrEntityNameRequired", err)
+		}
+		if !errors.Is(err, validator.ErrFKTableNotFound) {
+			t.Errorf("Validate() error = %v, want it to include ErrFKTableNotFound", err)
+		}
+	})
+}
+
+func TestHandlerDiff(t *testing.T) {
+	valid := []*entity.Entity{
+		{Name: "User", Fields: []entity.Field{{Name: "ID", Type: "int64", IsPrimary: true}}},
+	}
+	invalid := []*entity.Entity{
+		{Name: "", Fields: []entity.Field{}},
+	}
+
+	t.Run("valid models", func(t *testing.T) {
+		want := &entity.Migration{Up: []entity.MigrationStep{{SQL: "DROP TABLE \"user\";", Destructive: true}}}
+		handler := NewHandler(&mockGenerator{migration: want})
+
+		got, err := handler.Diff(context.Background(), &DiffCommand{From: valid, To: valid})
+		if err != nil {
+			t.Fatalf("Diff() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("Diff() = %v, want %v", got, want)
+		}
+	})
+
+	tests := []struct {
+		name string
+		cmd  *DiffCommand
+	}{
+		{name: "invalid from", cmd: &DiffCommand{From: invalid, To: valid}},
+		{name: "invalid to", cmd: &DiffCommand{From: valid, To: invalid}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHandler(&mockGenerator{})
+
+			_, err := handler.Diff(context.Background(), tt.cmd)
+			if !errors.Is(err, entity.ErrEntityNameRequired) {
+				t.Errorf("Diff() error = %v, want it to include ErrEntityNameRequired", err)
+			}
+		})
+	}
+}
+
+func TestHandlerGenerateSchemaOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  GenerateSchemaCommand
+		want SchemaOptions
+	}{
+		{name: "default", want: SchemaOptions{}},
+		{name: "if not exists", cmd: GenerateSchemaCommand{IfNotExists: true}, want: SchemaOptions{IfNotExists: true}},
+		{name: "drop", cmd: GenerateSchemaCommand{Drop: true}, want: SchemaOptions{Mode: ModeDrop}},
+		{name: "reset wins over drop", cmd: GenerateSchemaCommand{Drop: true, Reset: true}, want: SchemaOptions{Mode: ModeReset}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &mockGenerator{}
+			handler := NewHandler(gen)
+
+			cmd := tt.cmd
+			cmd.Entities = []*entity.Entity{
+				{Name: "User", Fields: []entity.Field{{Name: "ID", Type: "int64", IsPrimary: true}}},
+			}
+			if _, err := handler.GenerateSchema(context.Background(), &cmd); err != nil {
+				t.Fatalf("GenerateSchema() error = %v", err)
+			}
+			if gen.schemaOptions != tt.want {
+				t.Errorf("GenerateSchema() options = %+v, want %+v", gen.schemaOptions, tt.want)
+			}
+		})
+	}
 }
 
 func TestHandlerGeneratorError(t *testing.T) {
@@ -2,6 +2,7 @@ package application
 
 import (
 	"context"
+	"io/fs"
 
 	"github.com/n0xum/structify/internal/adapter"
 	"github.com/n0xum/structify/internal/domain/entity"
@@ -29,6 +30,39 @@ func (p *ParserWrapper) ParseFiles(ctx context.Context, paths []string) (map[str
 	return p.adapter.ToMap(structs), nil
 }
 
+// ParsePackages loads and type-checks the packages matched by patterns
+// (e.g. "./models/...") and returns their entities keyed by package name.
+func (p *ParserWrapper) ParsePackages(ctx context.Context, patterns []string) (map[string][]*entity.Entity, error) {
+	if err := p.parser.ParsePackages(patterns); err != nil {
+		return nil, err
+	}
+
+	structs := p.parser.GetStructs()
+	return p.adapter.ToMap(structs), nil
+}
+
+// ParseSource parses a single in-memory Go file and returns its entities
+// keyed by package name. Nothing is read from or written to disk.
+func (p *ParserWrapper) ParseSource(ctx context.Context, filename string, src []byte) (map[string][]*entity.Entity, error) {
+	if err := p.parser.ParseSource(filename, src); err != nil {
+		return nil, err
+	}
+
+	structs := p.parser.GetStructs()
+	return p.adapter.ToMap(structs), nil
+}
+
+// ParseFS parses the named files from fsys (e.g. an embed.FS) and returns
+// their entities keyed by package name.
+func (p *ParserWrapper) ParseFS(ctx context.Context, fsys fs.FS, paths []string) (map[string][]*entity.Entity, error) {
+	if err := p.parser.ParseFS(fsys, paths); err != nil {
+		return nil, err
+	}
+
+	structs := p.parser.GetStructs()
+	return p.adapter.ToMap(structs), nil
+}
+
 // ParseInterfaces parses Go files and returns discovered interfaces,
 // bound to an entity for repository method classification.
 func (p *ParserWrapper) ParseInterfaces(ctx context.Context, interfacePaths []string, ent *entity.Entity) ([]*entity.RepositoryInterface, error) {
@@ -36,7 +70,21 @@ func (p *ParserWrapper) ParseInterfaces(ctx context.Context, interfacePaths []st
 	if err := ifaceParser.ParseFiles(interfacePaths); err != nil {
 		return nil, err
 	}
+	return p.bindInterfaces(ifaceParser, ent), nil
+}
+
+// ParseSourceInterfaces is ParseInterfaces for a single in-memory Go file.
+func (p *ParserWrapper) ParseSourceInterfaces(ctx context.Context, filename string, src []byte, ent *entity.Entity) ([]*entity.RepositoryInterface, error) {
+	ifaceParser := parser.New()
+	if err := ifaceParser.ParseSource(filename, src); err != nil {
+		return nil, err
+	}
+	return p.bindInterfaces(ifaceParser, ent), nil
+}
 
+// bindInterfaces converts the interfaces found by ifaceParser into
+// repository interfaces for ent.
+func (p *ParserWrapper) bindInterfaces(ifaceParser *parser.Parser, ent *entity.Entity) []*entity.RepositoryInterface {
 	interfaces := ifaceParser.GetInterfaces()
 	var result []*entity.RepositoryInterface
 	for _, ifaces := range interfaces {
@@ -47,5 +95,5 @@ func (p *ParserWrapper) ParseInterfaces(ctx context.Context, interfacePaths []st
 			}
 		}
 	}
-	return result, nil
+	return result
 }
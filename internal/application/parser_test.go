@@ -3,7 +3,9 @@ package application
 import (
 	"context"
 	"github.com/n0xum/structify/internal/domain/entity"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestNewParserWrapper(t *testing.T) {
@@ -178,3 +180,67 @@ func TestParserWrapperParseInterfacesEmptyPaths(t *testing.T) {
 		t.Errorf("ParseInterfaces() should return nil or empty slice for empty paths, got %d items", len(result))
 	}
 }
+
+const userSource = `package models
+
+type User struct {
+	ID   int64 ` + "`db:\"pk\"`" + `
+	Name string
+}
+
+type UserRepository interface {
+	GetByID(ctx context.Context, id int64) (*User, error)
+}
+`
+
+func TestParserWrapperParseSource(t *testing.T) {
+	wrapper := NewParserWrapper()
+	ctx := context.Background()
+
+	result, err := wrapper.ParseSource(ctx, "user.go", []byte(userSource))
+	if err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	if len(result["models"]) != 1 || result["models"][0].Name != "User" {
+		t.Fatalf("ParseSource() = %v, want models.User", result)
+	}
+
+	repos, err := wrapper.ParseSourceInterfaces(ctx, "user.go", []byte(userSource), result["models"][0])
+	if err != nil {
+		t.Fatalf("ParseSourceInterfaces() error = %v", err)
+	}
+	if len(repos) != 1 || repos[0].Name != "UserRepository" {
+		t.Errorf("ParseSourceInterfaces() = %v, want UserRepository", repos)
+	}
+}
+
+func TestParserWrapperParseSourceSyntaxError(t *testing.T) {
+	wrapper := NewParserWrapper()
+
+	_, err := wrapper.ParseSource(context.Background(), "broken.go", []byte("package models\ntype User struct {"))
+	if err == nil {
+		t.Fatal("ParseSource() should return error for invalid source")
+	}
+	if !strings.Contains(err.Error(), "broken.go") {
+		t.Errorf("ParseSource() error = %v, want it to name broken.go", err)
+	}
+}
+
+func TestParserWrapperParseFS(t *testing.T) {
+	wrapper := NewParserWrapper()
+	fsys := fstest.MapFS{
+		"models/user.go": &fstest.MapFile{Data: []byte(userSource)},
+	}
+
+	result, err := wrapper.ParseFS(context.Background(), fsys, []string{"models/user.go"})
+	if err != nil {
+		t.Fatalf("ParseFS() error = %v", err)
+	}
+	if len(result["models"]) != 1 {
+		t.Errorf("ParseFS() returned %d entities, want 1", len(result["models"]))
+	}
+
+	if _, err := wrapper.ParseFS(context.Background(), fsys, []string{"missing.go"}); err == nil {
+		t.Error("ParseFS() should return error for missing file")
+	}
+}
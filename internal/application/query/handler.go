@@ -2,6 +2,8 @@ package query
 
 import (
 	"context"
+	"io/fs"
+	"sort"
 
 	"github.com/n0xum/structify/internal/domain/entity"
 )
@@ -12,6 +14,9 @@ type Handler struct {
 
 type Parser interface {
 	ParseFiles(ctx context.Context, paths []string) (map[string][]*entity.Entity, error)
+	ParsePackages(ctx context.Context, patterns []string) (map[string][]*entity.Entity, error)
+	ParseSource(ctx context.Context, filename string, src []byte) (map[string][]*entity.Entity, error)
+	ParseFS(ctx context.Context, fsys fs.FS, paths []string) (map[string][]*entity.Entity, error)
 }
 
 func NewHandler(parser Parser) *Handler {
@@ -22,6 +27,19 @@ func NewHandler(parser Parser) *Handler {
 
 type ParseQuery struct {
 	Files []string
+	// Packages holds package patterns such as "./models/..." that are
+	// loaded with full type information in addition to Files.
+	Packages []string
+	// Sources holds in-memory Go files parsed in addition to Files.
+	Sources []Source
+	// FS, when set, is the file system Files are read from instead of disk.
+	FS fs.FS
+}
+
+// Source is a Go file held in memory.
+type Source struct {
+	Filename string
+	Content  []byte
 }
 
 type ParseResult struct {
@@ -29,29 +47,84 @@ type ParseResult struct {
 	Package    string
 	Count      int
 	EntityList []*entity.Entity
+	// Diagnostics collects the problems reported for all entities, ordered
+	// by position.
+	Diagnostics []entity.Diagnostic
 }
 
 func (h *Handler) Parse(ctx context.Context, q *ParseQuery) (*ParseResult, error) {
-	entities, err := h.parser.ParseFiles(ctx, q.Files)
+	var entities map[string][]*entity.Entity
+	var err error
+	if q.FS != nil {
+		entities, err = h.parser.ParseFS(ctx, q.FS, q.Files)
+	} else {
+		entities, err = h.parser.ParseFiles(ctx, q.Files)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if len(q.Packages) > 0 {
+		pkgEntities, err := h.parser.ParsePackages(ctx, q.Packages)
+		if err != nil {
+			return nil, err
+		}
+		entities = mergeEntities(entities, pkgEntities)
+	}
+
+	for _, src := range q.Sources {
+		srcEntities, err := h.parser.ParseSource(ctx, src.Filename, src.Content)
+		if err != nil {
+			return nil, err
+		}
+		entities = mergeEntities(entities, srcEntities)
+	}
+
 	var allEntities []*entity.Entity
+	var diagnostics []entity.Diagnostic
 	var pkgName string
-	for pkg, pkgEntities := range entities {
+	for _, pkg := range packageNames(entities) {
 		pkgName = pkg
+		pkgEntities := entities[pkg]
 		allEntities = append(allEntities, pkgEntities...)
+		for _, ent := range pkgEntities {
+			diagnostics = append(diagnostics, ent.Diagnostics...)
+		}
 	}
+	entity.SortDiagnostics(diagnostics)
 
 	return &ParseResult{
-		Entities:   entities,
-		Package:    pkgName,
-		Count:      len(allEntities),
-		EntityList: allEntities,
+		Entities:    entities,
+		Package:     pkgName,
+		Count:       len(allEntities),
+		EntityList:  allEntities,
+		Diagnostics: diagnostics,
 	}, nil
 }
 
+// packageNames returns the packages of entities sorted by name, so that
+// entity lists built from the map come out in the same order on every run;
+// within a package, entities keep their file and declaration order.
+func packageNames(entities map[string][]*entity.Entity) []string {
+	names := make([]string, 0, len(entities))
+	for pkg := range entities {
+		names = append(names, pkg)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mergeEntities appends the entities of src to dst, package by package.
+func mergeEntities(dst, src map[string][]*entity.Entity) map[string][]*entity.Entity {
+	if dst == nil {
+		dst = make(map[string][]*entity.Entity)
+	}
+	for pkg, ents := range src {
+		dst[pkg] = append(dst[pkg], ents...)
+	}
+	return dst
+}
+
 type FindEntityQuery struct {
 	Name     string
 	Entities map[string][]*entity.Entity
@@ -74,8 +147,8 @@ type ListEntitiesQuery struct {
 
 func (h *Handler) ListEntities(ctx context.Context, q *ListEntitiesQuery) []*entity.Entity {
 	var result []*entity.Entity
-	for _, entities := range q.Entities {
-		result = append(result, entities...)
+	for _, pkg := range packageNames(q.Entities) {
+		result = append(result, q.Entities[pkg]...)
 	}
 	return result
 }
@@ -3,20 +3,38 @@ package query
 import (
 	"context"
 	"errors"
+	"io/fs"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/n0xum/structify/internal/domain/entity"
 )
 
 type mockParser struct {
-	entities map[string][]*entity.Entity
-	parseErr error
+	entities    map[string][]*entity.Entity
+	pkgEntities map[string][]*entity.Entity
+	srcEntities map[string][]*entity.Entity
+	fsEntities  map[string][]*entity.Entity
+	parseErr    error
 }
 
 func (m *mockParser) ParseFiles(ctx context.Context, paths []string) (map[string][]*entity.Entity, error) {
 	return m.entities, m.parseErr
 }
 
+func (m *mockParser) ParsePackages(ctx context.Context, patterns []string) (map[string][]*entity.Entity, error) {
+	return m.pkgEntities, m.parseErr
+}
+
+func (m *mockParser) ParseSource(ctx context.Context, filename string, src []byte) (map[string][]*entity.Entity, error) {
+	return m.srcEntities, m.parseErr
+}
+
+func (m *mockParser) ParseFS(ctx context.Context, fsys fs.FS, paths []string) (map[string][]*entity.Entity, error) {
+	return m.fsEntities, m.parseErr
+}
+
 func TestHandlerParse(t *testing.T) {
 	t.Run("successful parse", func(t *testing.T) {
 		entities := map[string][]*entity.Entity{
@@ -58,6 +76,93 @@ func TestHandlerParse(t *testing.T) {
 			t.Errorf("Parse() error = %v, want %v", err, parseErr)
 		}
 	})
+
+	t.Run("packages merged with files", func(t *testing.T) {
+		parser := &mockParser{
+			entities: map[string][]*entity.Entity{
+				"models": {{Name: "User", Fields: []entity.Field{{Name: "ID", Type: "int64"}}}},
+			},
+			pkgEntities: map[string][]*entity.Entity{
+				"models": {{Name: "Order", Fields: []entity.Field{{Name: "ID", Type: "int64"}}}},
+			},
+		}
+		handler := NewHandler(parser)
+
+		q := &ParseQuery{Files: []string{"user.go"}, Packages: []string{"./models/..."}}
+		result, err := handler.Parse(context.Background(), q)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if result.Count != 2 {
+			t.Errorf("Parse() Count = %d, want 2", result.Count)
+		}
+	})
+
+	t.Run("in-memory sources", func(t *testing.T) {
+		parser := &mockParser{
+			srcEntities: map[string][]*entity.Entity{
+				"models": {{Name: "User", Fields: []entity.Field{{Name: "ID", Type: "int64"}}}},
+			},
+		}
+		handler := NewHandler(parser)
+
+		q := &ParseQuery{Sources: []Source{{Filename: "user.go", Content: []byte("package models")}}}
+		result, err := handler.Parse(context.Background(), q)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if result.Count != 1 {
+			t.Errorf("Parse() Count = %d, want 1", result.Count)
+		}
+	})
+
+	t.Run("files from fs", func(t *testing.T) {
+		parser := &mockParser{
+			entities: map[string][]*entity.Entity{
+				"disk": {{Name: "Disk", Fields: []entity.Field{{Name: "ID", Type: "int64"}}}},
+			},
+			fsEntities: map[string][]*entity.Entity{
+				"models": {{Name: "User", Fields: []entity.Field{{Name: "ID", Type: "int64"}}}},
+			},
+		}
+		handler := NewHandler(parser)
+
+		q := &ParseQuery{Files: []string{"user.go"}, FS: fstest.MapFS{}}
+		result, err := handler.Parse(context.Background(), q)
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if result.Package != "models" || result.Count != 1 {
+			t.Errorf("Parse() = %s/%d, want models/1", result.Package, result.Count)
+		}
+	})
+
+	t.Run("several packages in a stable order", func(t *testing.T) {
+		parser := &mockParser{
+			entities: map[string][]*entity.Entity{
+				"orders":  {{Name: "Order"}, {Name: "LineItem"}},
+				"billing": {{Name: "Invoice"}},
+				"users":   {{Name: "User"}, {Name: "Profile"}},
+				"audit":   {{Name: "Event"}},
+			},
+		}
+		handler := NewHandler(parser)
+		want := "Event Invoice Order LineItem User Profile"
+
+		for i := 0; i < 20; i++ {
+			result, err := handler.Parse(context.Background(), &ParseQuery{Files: []string{"models.go"}})
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			var names []string
+			for _, ent := range result.EntityList {
+				names = append(names, ent.Name)
+			}
+			if got := strings.Join(names, " "); got != want {
+				t.Fatalf("Parse() EntityList = %s, want %s", got, want)
+			}
+		}
+	})
 }
 
 func TestHandlerFindEntity(t *testing.T) {
@@ -125,6 +230,9 @@ func TestHandlerListEntities(t *testing.T) {
 	result := handler.ListEntities(context.Background(), q)
 
 	if len(result) != 3 {
-		t.Errorf("ListEntities() length = %d, want 3", len(result))
+		t.Fatalf("ListEntities() length = %d, want 3", len(result))
+	}
+	if result[0].Name != "Admin" || result[1].Name != "User" || result[2].Name != "Product" {
+		t.Errorf("ListEntities() = %s, %s, %s, want Admin, User, Product", result[0].Name, result[1].Name, result[2].Name)
 	}
 }
@@ -0,0 +1,286 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/n0xum/structify/internal/domain/entity"
+)
+
+// SnapshotVersion is the format version written by WriteSnapshot.
+const SnapshotVersion = 1
+
+// ErrSnapshotVersion is returned by ReadSnapshot for a snapshot written in
+// a format this version of structify does not read.
+var ErrSnapshotVersion = errors.New("unsupported snapshot version")
+
+// snapshot is a model saved as JSON, so that a later version of the model
+// can be diffed against it once the old Go sources are gone. It has its own
+// types with fixed JSON names, so that renaming a field of the domain
+// entities does not silently change the format.
+type snapshot struct {
+	Version  int              `json:"version"`
+	Entities []snapshotEntity `json:"entities"`
+}
+
+type snapshotEntity struct {
+	Name          string                `json:"name"`
+	Package       string                `json:"package,omitempty"`
+	TableName     string                `json:"table_name,omitempty"`
+	Schema        string                `json:"schema,omitempty"`
+	Unlogged      bool                  `json:"unlogged,omitempty"`
+	StorageParams []string              `json:"storage_params,omitempty"`
+	Checks        []string              `json:"checks,omitempty"`
+	Exclusions    []string              `json:"exclusions,omitempty"`
+	PartitionBy   *snapshotPartitioning `json:"partition_by,omitempty"`
+	Partitions    []snapshotPartition   `json:"partitions,omitempty"`
+	View          bool                  `json:"view,omitempty"`
+	Materialized  bool                  `json:"materialized,omitempty"`
+	ViewSQL       string                `json:"view_sql,omitempty"`
+	Comment       string                `json:"comment,omitempty"`
+	Directives    []snapshotDirective   `json:"directives,omitempty"`
+	Fields        []snapshotField       `json:"fields"`
+}
+
+type snapshotPartitioning struct {
+	Method  string   `json:"method"`
+	Columns []string `json:"columns"`
+}
+
+type snapshotPartition struct {
+	Name  string `json:"name"`
+	Bound string `json:"bound"`
+}
+
+type snapshotDirective struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+type snapshotField struct {
+	Name           string               `json:"name"`
+	Type           string               `json:"type"`
+	UnderlyingType string               `json:"underlying_type,omitempty"`
+	TableName      string               `json:"table_name,omitempty"`
+	Column         string               `json:"column,omitempty"`
+	Selector       string               `json:"selector,omitempty"`
+	IsPrimary      bool                 `json:"primary,omitempty"`
+	IsUnique       bool                 `json:"unique,omitempty"`
+	IsIgnored      bool                 `json:"ignored,omitempty"`
+	IsNullable     bool                 `json:"nullable,omitempty"`
+	IsNotNull      bool                 `json:"not_null,omitempty"`
+	Identity       string               `json:"identity,omitempty"`
+	GeneratedExpr  string               `json:"generated,omitempty"`
+	CheckExpr      string               `json:"check,omitempty"`
+	DefaultVal     string               `json:"default,omitempty"`
+	EnumValues     []string             `json:"enum,omitempty"`
+	SQLType        string               `json:"sql_type,omitempty"`
+	Size           int                  `json:"size,omitempty"`
+	Precision      int                  `json:"precision,omitempty"`
+	Scale          int                  `json:"scale,omitempty"`
+	Comment        string               `json:"comment,omitempty"`
+	IndexName      string               `json:"index,omitempty"`
+	IsIndexUnique  bool                 `json:"index_unique,omitempty"`
+	IndexGroup     string               `json:"index_group,omitempty"`
+	IndexMethod    string               `json:"index_method,omitempty"`
+	IndexWhere     string               `json:"index_where,omitempty"`
+	IndexInclude   []string             `json:"index_include,omitempty"`
+	IndexExpr      string               `json:"index_expr,omitempty"`
+	IndexDesc      bool                 `json:"index_desc,omitempty"`
+	IndexNullsLast bool                 `json:"index_nulls_last,omitempty"`
+	IndexOrder     int                  `json:"index_order,omitempty"`
+	FKReference    *snapshotFKReference `json:"fk,omitempty"`
+	FKOnDelete     string               `json:"fk_on_delete,omitempty"`
+	FKOnUpdate     string               `json:"fk_on_update,omitempty"`
+	FKGroup        string               `json:"fk_group,omitempty"`
+}
+
+type snapshotFKReference struct {
+	Table   string   `json:"table"`
+	Column  string   `json:"column,omitempty"`
+	Columns []string `json:"columns,omitempty"`
+}
+
+// WriteSnapshot writes entities to w as an indented JSON snapshot. Source
+// positions and diagnostics are left out; they belong to the Go sources,
+// not to the schema.
+func WriteSnapshot(w io.Writer, entities []*entity.Entity) error {
+	s := snapshot{Version: SnapshotVersion, Entities: make([]snapshotEntity, len(entities))}
+	for i, ent := range entities {
+		s.Entities[i] = toSnapshotEntity(ent)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// ReadSnapshot reads the entities of a snapshot written by WriteSnapshot.
+// A key the format does not define is an error rather than being dropped.
+func ReadSnapshot(r io.Reader) ([]*entity.Entity, error) {
+	var s snapshot
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("reading snapshot: %w", err)
+	}
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrSnapshotVersion, s.Version)
+	}
+
+	entities := make([]*entity.Entity, len(s.Entities))
+	for i, ent := range s.Entities {
+		entities[i] = ent.toEntity()
+	}
+	return entities, nil
+}
+
+func toSnapshotEntity(ent *entity.Entity) snapshotEntity {
+	s := snapshotEntity{
+		Name:          ent.Name,
+		Package:       ent.Package,
+		TableName:     ent.TableName,
+		Schema:        ent.Schema,
+		Unlogged:      ent.Unlogged,
+		StorageParams: ent.StorageParams,
+		Checks:        ent.Checks,
+		Exclusions:    ent.Exclusions,
+		View:          ent.View,
+		Materialized:  ent.Materialized,
+		ViewSQL:       ent.ViewSQL,
+		Comment:       ent.Comment,
+		Fields:        make([]snapshotField, len(ent.Fields)),
+	}
+	if ent.PartitionBy != nil {
+		s.PartitionBy = &snapshotPartitioning{Method: ent.PartitionBy.Method, Columns: ent.PartitionBy.Columns}
+	}
+	for _, partition := range ent.Partitions {
+		s.Partitions = append(s.Partitions, snapshotPartition{Name: partition.Name, Bound: partition.Bound})
+	}
+	for _, directive := range ent.Directives {
+		s.Directives = append(s.Directives, snapshotDirective{Key: directive.Key, Value: directive.Value})
+	}
+	for i, field := range ent.Fields {
+		s.Fields[i] = toSnapshotField(field)
+	}
+	return s
+}
+
+func (s snapshotEntity) toEntity() *entity.Entity {
+	ent := &entity.Entity{
+		Name:          s.Name,
+		Package:       s.Package,
+		TableName:     s.TableName,
+		Schema:        s.Schema,
+		Unlogged:      s.Unlogged,
+		StorageParams: s.StorageParams,
+		Checks:        s.Checks,
+		Exclusions:    s.Exclusions,
+		View:          s.View,
+		Materialized:  s.Materialized,
+		ViewSQL:       s.ViewSQL,
+		Comment:       s.Comment,
+		Fields:        make([]entity.Field, len(s.Fields)),
+	}
+	if s.PartitionBy != nil {
+		ent.PartitionBy = &entity.Partitioning{Method: s.PartitionBy.Method, Columns: s.PartitionBy.Columns}
+	}
+	for _, partition := range s.Partitions {
+		ent.Partitions = append(ent.Partitions, entity.Partition{Name: partition.Name, Bound: partition.Bound})
+	}
+	for _, directive := range s.Directives {
+		ent.Directives = append(ent.Directives, entity.Directive{Key: directive.Key, Value: directive.Value})
+	}
+	for i, field := range s.Fields {
+		ent.Fields[i] = field.toField()
+	}
+	return ent
+}
+
+func toSnapshotField(field entity.Field) snapshotField {
+	s := snapshotField{
+		Name:           field.Name,
+		Type:           field.Type,
+		UnderlyingType: field.UnderlyingType,
+		TableName:      field.TableName,
+		Column:         field.Column,
+		Selector:       field.Selector,
+		IsPrimary:      field.IsPrimary,
+		IsUnique:       field.IsUnique,
+		IsIgnored:      field.IsIgnored,
+		IsNullable:     field.IsNullable,
+		IsNotNull:      field.IsNotNull,
+		Identity:       field.Identity,
+		GeneratedExpr:  field.GeneratedExpr,
+		CheckExpr:      field.CheckExpr,
+		DefaultVal:     field.DefaultVal,
+		EnumValues:     field.EnumValues,
+		SQLType:        field.SQLType,
+		Size:           field.Size,
+		Precision:      field.Precision,
+		Scale:          field.Scale,
+		Comment:        field.Comment,
+		IndexName:      field.IndexName,
+		IsIndexUnique:  field.IsIndexUnique,
+		IndexGroup:     field.IndexGroup,
+		IndexMethod:    field.IndexMethod,
+		IndexWhere:     field.IndexWhere,
+		IndexInclude:   field.IndexInclude,
+		IndexExpr:      field.IndexExpr,
+		IndexDesc:      field.IndexDesc,
+		IndexNullsLast: field.IndexNullsLast,
+		IndexOrder:     field.IndexOrder,
+		FKOnDelete:     field.FKOnDelete,
+		FKOnUpdate:     field.FKOnUpdate,
+		FKGroup:        field.FKGroup,
+	}
+	if ref := field.FKReference; ref != nil {
+		s.FKReference = &snapshotFKReference{Table: ref.Table, Column: ref.Column, Columns: ref.Columns}
+	}
+	return s
+}
+
+func (s snapshotField) toField() entity.Field {
+	field := entity.Field{
+		Name:           s.Name,
+		Type:           s.Type,
+		UnderlyingType: s.UnderlyingType,
+		TableName:      s.TableName,
+		Column:         s.Column,
+		Selector:       s.Selector,
+		IsPrimary:      s.IsPrimary,
+		IsUnique:       s.IsUnique,
+		IsIgnored:      s.IsIgnored,
+		IsNullable:     s.IsNullable,
+		IsNotNull:      s.IsNotNull,
+		Identity:       s.Identity,
+		GeneratedExpr:  s.GeneratedExpr,
+		CheckExpr:      s.CheckExpr,
+		DefaultVal:     s.DefaultVal,
+		EnumValues:     s.EnumValues,
+		SQLType:        s.SQLType,
+		Size:           s.Size,
+		Precision:      s.Precision,
+		Scale:          s.Scale,
+		Comment:        s.Comment,
+		IndexName:      s.IndexName,
+		IsIndexUnique:  s.IsIndexUnique,
+		IndexGroup:     s.IndexGroup,
+		IndexMethod:    s.IndexMethod,
+		IndexWhere:     s.IndexWhere,
+		IndexInclude:   s.IndexInclude,
+		IndexExpr:      s.IndexExpr,
+		IndexDesc:      s.IndexDesc,
+		IndexNullsLast: s.IndexNullsLast,
+		IndexOrder:     s.IndexOrder,
+		FKOnDelete:     s.FKOnDelete,
+		FKOnUpdate:     s.FKOnUpdate,
+		FKGroup:        s.FKGroup,
+	}
+	if ref := s.FKReference; ref != nil {
+		field.FKReference = &entity.FKReference{Table: ref.Table, Column: ref.Column, Columns: ref.Columns}
+	}
+	return field
+}
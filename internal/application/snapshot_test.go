@@ -0,0 +1,115 @@
+package application
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/n0xum/structify/internal/domain/entity"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	entities := []*entity.Entity{
+		{
+			Name:   "Order",
+			Schema: "sales",
+			Checks: []string{"total > 0"},
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "CustomerID", Type: "int64", FKReference: &entity.FKReference{Table: "customer", Column: "id"}, FKOnDelete: "CASCADE"},
+				{Name: "Status", Type: "string", EnumValues: []string{"new", "paid"}, IndexName: "idx_order_status"},
+			},
+			PartitionBy: &entity.Partitioning{Method: "RANGE", Columns: []string{"id"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, entities); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+
+	got, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, entities) {
+		t.Errorf("ReadSnapshot() = %+v, want %+v", got[0], entities[0])
+	}
+}
+
+// Every field of an entity that describes the schema must survive a
+// snapshot; only source positions and diagnostics are left out.
+func TestSnapshotRoundTripAllFields(t *testing.T) {
+	ent := &entity.Entity{}
+	fillFields(reflect.ValueOf(ent).Elem())
+
+	var buf bytes.Buffer
+	if err := WriteSnapshot(&buf, []*entity.Entity{ent}); err != nil {
+		t.Fatalf("WriteSnapshot() error = %v", err)
+	}
+	got, err := ReadSnapshot(&buf)
+	if err != nil {
+		t.Fatalf("ReadSnapshot() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []*entity.Entity{ent}) {
+		t.Errorf("ReadSnapshot() = %+v, want %+v", got[0], ent)
+	}
+}
+
+// fillFields sets every field of the struct v to a value other than its
+// zero value, except Pos and Diagnostics.
+func fillFields(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		if name := v.Type().Field(i).Name; name == "Pos" || name == "Diagnostics" {
+			continue
+		}
+		fillValue(v.Field(i))
+	}
+}
+
+func fillValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int:
+		v.SetInt(1)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fillValue(v.Index(0))
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillValue(v.Elem())
+	case reflect.Struct:
+		fillFields(v)
+	}
+}
+
+func TestReadSnapshotErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{name: "unknown version", input: `{"version": 2, "entities": []}`, wantErr: ErrSnapshotVersion},
+		{name: "missing version", input: `{"entities": []}`, wantErr: ErrSnapshotVersion},
+		{name: "unknown entity key", input: `{"version": 1, "entities": [{"name": "User", "colour": "red", "fields": []}]}`},
+		{name: "unknown field key", input: `{"version": 1, "entities": [{"name": "User", "fields": [{"name": "ID", "type": "int64", "pk": true}]}]}`},
+		{name: "not json", input: `CREATE TABLE "user" ();`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadSnapshot(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatal("ReadSnapshot() error = nil, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReadSnapshot() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
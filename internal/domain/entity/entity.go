@@ -2,6 +2,7 @@ package entity
 
 import (
 	"errors"
+	"strings"
 
 	"github.com/n0xum/structify/internal/util"
 )
@@ -17,6 +18,77 @@ type Entity struct {
 	Fields    []Field
 	TableName string
 	Package   string
+
+	// Schema is the PostgreSQL schema the table belongs to
+	// Parsed from //structify:schema=name
+	Schema string
+
+	// Unlogged creates the table as UNLOGGED
+	// Parsed from //structify:unlogged
+	Unlogged bool
+
+	// StorageParams holds storage parameters for the WITH (...) clause
+	// Parsed from //structify:with="fillfactor=70,autovacuum_enabled=false"
+	StorageParams []string
+
+	// Checks holds table-level CHECK expressions spanning several columns
+	// Parsed from //structify:check="start_at < end_at"
+	Checks []string
+
+	// Exclusions holds EXCLUDE constraints, written as the text following
+	// the EXCLUDE keyword
+	// Parsed from //structify:exclude="USING gist (room_id WITH =, during WITH &&)"
+	Exclusions []string
+
+	// PartitionBy makes the table a partitioned table
+	// Parsed from //structify:partition_by=range(created_at)
+	PartitionBy *Partitioning
+
+	// Partitions holds the child partitions created together with a
+	// partitioned table
+	// Parsed from //structify:partition="events_eu IN ('de', 'fr')" and
+	// //structify:partition_monthly=2026-01,12
+	Partitions []Partition
+
+	// View makes the struct a read-only view defined by ViewSQL instead of
+	// a table
+	// Parsed from //structify:view or //structify:view=materialized
+	View         bool
+	Materialized bool
+
+	// ViewSQL is the query defining the view
+	// Parsed from //sql: doc comment lines or the file named by
+	// //structify:sql_file=views/active_users.sql
+	ViewSQL string
+
+	// Comment is the struct's doc comment, emitted as COMMENT ON TABLE
+	Comment string
+
+	// Directives holds every //structify: directive of the struct in
+	// declaration order, including those without a dedicated field
+	Directives []Directive
+
+	// Diagnostics holds the problems found while mapping the struct's
+	// db tags, such as unknown keys or malformed foreign keys
+	Diagnostics []Diagnostic
+}
+
+// Directive is a struct-level option declared in a //structify: doc comment
+type Directive struct {
+	Key   string
+	Value string
+}
+
+// Partitioning is the partitioning method and key of a partitioned table
+type Partitioning struct {
+	Method  string   // RANGE, LIST or HASH
+	Columns []string // Partition key columns
+}
+
+// Partition is a child partition of a partitioned table
+type Partition struct {
+	Name  string
+	Bound string // FOR VALUES ... or DEFAULT
 }
 
 func (e *Entity) Validate() error {
@@ -61,6 +133,16 @@ func (e *Entity) GetPrimaryKeyFields() []Field {
 	return pkFields
 }
 
+// FieldByName returns the field with the given Go name, or nil
+func (e *Entity) FieldByName(name string) *Field {
+	for i := range e.Fields {
+		if e.Fields[i].Name == name {
+			return &e.Fields[i]
+		}
+	}
+	return nil
+}
+
 func (e *Entity) HasPrimaryKey() bool {
 	return len(e.GetPrimaryKeyFields()) > 0
 }
@@ -110,6 +192,18 @@ func (e *Entity) HasCompositeUniqueConstraints() bool {
 	return false
 }
 
+// Directive returns the value of the last directive with the given key and
+// whether the struct declares it at all
+func (e *Entity) Directive(key string) (string, bool) {
+	value, found := "", false
+	for _, d := range e.Directives {
+		if d.Key == key {
+			value, found = d.Value, true
+		}
+	}
+	return value, found
+}
+
 func (e *Entity) GetGenerateableFields() []Field {
 	var fields []Field
 	for _, f := range e.Fields {
@@ -127,8 +221,56 @@ func (e *Entity) GetTableName() string {
 	return util.ToSnakeCase(e.Name)
 }
 
-// GetQuotedTableName returns the table name wrapped in double quotes so that
-// PostgreSQL reserved words (e.g. "order", "user") are always safe to use in DML.
+// GetQualifiedTableName returns the table name prefixed with its schema
+// (billing.invoice), or the bare table name when the entity has no schema.
+func (e *Entity) GetQualifiedTableName() string {
+	return QualifiedName(e.Schema, e.GetTableName())
+}
+
+// GetQuotedTableName returns the schema-qualified table name with each part
+// wrapped in double quotes so that PostgreSQL reserved words (e.g. "order",
+// "user") are always safe to use in DML.
 func (e *Entity) GetQuotedTableName() string {
-	return `"` + e.GetTableName() + `"`
+	return QuoteQualifiedName(e.GetQualifiedTableName())
+}
+
+// HasTable reports whether name refers to the entity's table. A bare name
+// matches the table in any schema; a qualified name must match the schema too.
+func (e *Entity) HasTable(name string) bool {
+	if schema, table := SplitQualifiedName(name); schema != "" {
+		return schema == e.Schema && table == e.GetTableName()
+	}
+	return name == e.GetTableName()
+}
+
+// QualifiedName joins a schema and a table name as schema.table; the schema
+// is left out when empty.
+func QualifiedName(schema, table string) string {
+	if schema == "" {
+		return table
+	}
+	return schema + "." + table
+}
+
+// SplitQualifiedName splits schema.table into its parts. The schema is ""
+// for a bare table name.
+func SplitQualifiedName(name string) (schema, table string) {
+	if schema, table, ok := strings.Cut(name, "."); ok {
+		return schema, table
+	}
+	return "", name
+}
+
+// QuoteQualifiedName quotes both parts of a schema.table name as
+// identifiers ("billing"."invoice").
+func QuoteQualifiedName(name string) string {
+	schema, table := SplitQualifiedName(name)
+	if schema == "" {
+		return quoteIdentifier(table)
+	}
+	return quoteIdentifier(schema) + "." + quoteIdentifier(table)
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
 }
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"go/token"
 	"testing"
 )
 
@@ -199,6 +200,68 @@ func TestEntityGetTableName(t *testing.T) {
 	}
 }
 
+func TestEntityQualifiedTableName(t *testing.T) {
+	tests := []struct {
+		name          string
+		schema        string
+		tableName     string
+		wantQualified string
+		wantQuoted    string
+	}{
+		{
+			name:          "no schema",
+			tableName:     "invoice",
+			wantQualified: "invoice",
+			wantQuoted:    `"invoice"`,
+		},
+		{
+			name:          "with schema",
+			schema:        "billing",
+			tableName:     "invoice",
+			wantQualified: "billing.invoice",
+			wantQuoted:    `"billing"."invoice"`,
+		},
+		{
+			name:          "reserved word",
+			schema:        "shop",
+			tableName:     "order",
+			wantQualified: "shop.order",
+			wantQuoted:    `"shop"."order"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Entity{Name: "Invoice", Schema: tt.schema, TableName: tt.tableName}
+			if got := e.GetQualifiedTableName(); got != tt.wantQualified {
+				t.Errorf("GetQualifiedTableName() = %v, want %v", got, tt.wantQualified)
+			}
+			if got := e.GetQuotedTableName(); got != tt.wantQuoted {
+				t.Errorf("GetQuotedTableName() = %v, want %v", got, tt.wantQuoted)
+			}
+		})
+	}
+}
+
+func TestEntityHasTable(t *testing.T) {
+	e := &Entity{Name: "Invoice", Schema: "billing"}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"invoice", true},
+		{"billing.invoice", true},
+		{"shop.invoice", false},
+		{"invoices", false},
+	}
+	for _, tt := range tests {
+		if got := e.HasTable(tt.name); got != tt.want {
+			t.Errorf("HasTable(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
 func TestFieldShouldGenerate(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -483,3 +546,92 @@ func TestEntityGetPrimaryKeyFields(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldColumnNameAndSelector(t *testing.T) {
+	tests := []struct {
+		name         string
+		field        Field
+		wantColumn   string
+		wantSelector string
+	}{
+		{
+			name:         "derived from name",
+			field:        Field{Name: "UserID", Type: "int64"},
+			wantColumn:   "user_id",
+			wantSelector: "UserID",
+		},
+		{
+			name:         "embedded value object",
+			field:        Field{Name: "ShippingStreet", Type: "string", Column: "ship_street", Selector: "Shipping.Street"},
+			wantColumn:   "ship_street",
+			wantSelector: "Shipping.Street",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.field.ColumnName(); got != tt.wantColumn {
+				t.Errorf("ColumnName() = %v, want %v", got, tt.wantColumn)
+			}
+			if got := tt.field.GoSelector(); got != tt.wantSelector {
+				t.Errorf("GoSelector() = %v, want %v", got, tt.wantSelector)
+			}
+		})
+	}
+}
+
+func TestEntityDirective(t *testing.T) {
+	ent := &Entity{
+		Name: "Event",
+		Directives: []Directive{
+			{Key: "unlogged"},
+			{Key: "partition", Value: "a"},
+			{Key: "partition", Value: "b"},
+		},
+	}
+
+	if _, ok := ent.Directive("unlogged"); !ok {
+		t.Error("Directive(unlogged) not found")
+	}
+	if value, _ := ent.Directive("partition"); value != "b" {
+		t.Errorf("Directive(partition) = %q, want last value b", value)
+	}
+	if _, ok := ent.Directive("missing"); ok {
+		t.Error("Directive(missing) found, want not found")
+	}
+}
+
+func TestDiagnosticString(t *testing.T) {
+	d := Diagnostic{
+		Pos:      token.Position{Filename: "user.go", Line: 3, Column: 7},
+		Severity: SeverityError,
+		Message:  "bad tag",
+	}
+	if got := d.String(); got != "user.go:3:7: error: bad tag" {
+		t.Errorf("String() = %q, want %q", got, "user.go:3:7: error: bad tag")
+	}
+
+	d = Diagnostic{Severity: SeverityWarning, Message: "odd tag"}
+	if got := d.String(); got != "warning: odd tag" {
+		t.Errorf("String() = %q, want %q", got, "warning: odd tag")
+	}
+}
+
+func TestSortDiagnostics(t *testing.T) {
+	diags := []Diagnostic{
+		{Pos: token.Position{Filename: "b.go", Line: 1, Column: 1}},
+		{Pos: token.Position{Filename: "a.go", Line: 2, Column: 5}},
+		{Pos: token.Position{Filename: "a.go", Line: 2, Column: 1}},
+	}
+	SortDiagnostics(diags)
+
+	want := []string{"a.go:2:1", "a.go:2:5", "b.go:1:1"}
+	for i, w := range want {
+		if got := diags[i].Pos.String(); got != w {
+			t.Errorf("diags[%d] = %s, want %s", i, got, w)
+		}
+	}
+	if HasErrors(diags) {
+		t.Error("HasErrors() = true for warnings only")
+	}
+}
@@ -1,5 +1,11 @@
 package entity
 
+import (
+	"go/token"
+
+	"github.com/n0xum/structify/internal/util"
+)
+
 // FKReference represents a foreign key reference to another table
 type FKReference struct {
 	Table   string   // Referenced table name
@@ -15,6 +21,33 @@ type Field struct {
 	IsIgnored bool
 	TableName string
 
+	// IsNullable and IsNotNull override the nullability derived from the Go
+	// type, which makes pointers and sql.Null types nullable
+	// Parsed from db:"null" and db:"notnull"
+	IsNullable bool
+	IsNotNull  bool
+
+	// Identity stores how the database generates the column value:
+	// "identity", "serial" or "bigserial"; empty when the value is inserted
+	// Parsed from db:"pk,identity", db:"pk,serial" or db:"pk,bigserial"
+	Identity string
+
+	// GeneratedExpr stores the expression of a stored generated column
+	// Parsed from db:"generated:lower(email)"
+	GeneratedExpr string
+
+	// UnderlyingType stores the resolved underlying Go type of a named type
+	// (e.g. "string" for `type Email string`), empty when not resolved
+	UnderlyingType string
+
+	// Column stores an explicit column name, e.g. for fields inlined from a
+	// db:"embed,prefix:addr_" struct. Empty derives the name from Name
+	Column string
+
+	// Selector stores the Go selector path reaching the field on the model
+	// struct (e.g. "Address.Street"). Empty means the field is accessed by Name
+	Selector string
+
 	// CheckExpr stores the expression for CHECK constraint
 	// Parsed from db:"check:expression" tag
 	CheckExpr string
@@ -35,8 +68,35 @@ type Field struct {
 	// Fields with the same IndexName belong to the same index
 	IndexGroup string
 
+	// IndexMethod stores the access method of the index
+	// Parsed from db:"index:name,using:gin"
+	IndexMethod string
+
+	// IndexWhere stores the predicate of a partial index
+	// Parsed from db:"index:name,where:deleted_at IS NULL"
+	IndexWhere string
+
+	// IndexInclude stores the covering columns of the index
+	// Parsed from db:"index:name,include:name,email"
+	IndexInclude []string
+
+	// IndexExpr stores an expression indexed instead of the column
+	// Parsed from db:"index:name,expr:lower(email)"
+	IndexExpr string
+
+	// IndexDesc and IndexNullsLast order the field's index element
+	// Parsed from db:"index:name,desc,nulls_last"
+	IndexDesc      bool
+	IndexNullsLast bool
+
+	// IndexOrder stores the position of the field within a composite index,
+	// starting at 1; zero keeps the field order
+	// Parsed from db:"index:name,order:2"
+	IndexOrder int
+
 	// EnumValues stores the allowed enum values
-	// Parsed from db:"enum:value1,value2,value3" tag
+	// Parsed from db:"enum:value1,value2,value3" tag, or derived from the
+	// typed constants declared for the field's named type
 	EnumValues []string
 
 	// FKReference stores the foreign key reference
@@ -55,6 +115,26 @@ type Field struct {
 	// Fields with the same FKGroup form a composite FK constraint
 	// Parsed from db:"fk:constraint_name,table,column"
 	FKGroup string
+
+	// SQLType overrides the mapped column type
+	// Parsed from db:"type:NUMERIC(12,2)"
+	SQLType string
+
+	// Size stores the length of a VARCHAR column
+	// Parsed from db:"size:64"
+	Size int
+
+	// Precision and Scale store the precision of a NUMERIC column, or the
+	// fractional seconds precision of a time column
+	// Parsed from db:"precision:12,scale:2"
+	Precision int
+	Scale     int
+
+	// Comment stores the field's doc comment, emitted as COMMENT ON COLUMN
+	Comment string
+
+	// Pos stores the source position of the field, if known
+	Pos token.Position
 }
 
 func (f *Field) ShouldGenerate() bool {
@@ -66,3 +146,26 @@ func (f *Field) ShouldGenerate() bool {
 	}
 	return true
 }
+
+// IsGeneratedByDatabase reports whether the database generates the column
+// value, so it is left out of INSERT and UPDATE statements
+func (f *Field) IsGeneratedByDatabase() bool {
+	return f.Identity != "" || f.GeneratedExpr != ""
+}
+
+// ColumnName returns the SQL column name of the field
+func (f *Field) ColumnName() string {
+	if f.Column != "" {
+		return f.Column
+	}
+	return util.ToSnakeCase(f.Name)
+}
+
+// GoSelector returns the selector used to access the field on a value of the
+// model struct, e.g. "Email" or "Address.Street" for an embedded value object
+func (f *Field) GoSelector() string {
+	if f.Selector != "" {
+		return f.Selector
+	}
+	return f.Name
+}
@@ -11,6 +11,7 @@ const (
 	MethodFindBy
 	MethodSmartQuery // Auto-generated SQL from patterns
 	MethodCustomSQL
+	MethodRefresh // REFRESH MATERIALIZED VIEW
 )
 
 type RepositoryInterface struct {
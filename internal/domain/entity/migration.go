@@ -0,0 +1,55 @@
+package entity
+
+import "strings"
+
+// Migration holds the statements taking a database from one version of the
+// model to another (Up) and back again (Down).
+type Migration struct {
+	Up   []MigrationStep
+	Down []MigrationStep
+}
+
+// MigrationStep is one statement of a migration. A destructive step loses
+// data, such as dropping a column; Warning says what is lost, or for other
+// steps why the statement may fail on existing rows. A step without SQL is
+// a change the migration cannot make; Warning says what to do by hand.
+type MigrationStep struct {
+	SQL         string
+	Destructive bool
+	Warning     string
+}
+
+// HasChanges reports whether the two model versions differ.
+func (m *Migration) HasChanges() bool {
+	return len(m.Up) > 0
+}
+
+// UpSQL renders the Up steps.
+func (m *Migration) UpSQL() string {
+	return renderSteps(m.Up)
+}
+
+// DownSQL renders the Down steps.
+func (m *Migration) DownSQL() string {
+	return renderSteps(m.Down)
+}
+
+// renderSteps writes one statement per paragraph. A destructive step is
+// preceded by a "-- DESTRUCTIVE:" comment, any other warning by
+// "-- WARNING:", which stands alone for a step without SQL.
+func renderSteps(steps []MigrationStep) string {
+	var sb strings.Builder
+	for _, step := range steps {
+		switch {
+		case step.Destructive:
+			sb.WriteString("-- DESTRUCTIVE: " + step.Warning + "\n")
+		case step.Warning != "":
+			sb.WriteString("-- WARNING: " + step.Warning + "\n")
+		}
+		if step.SQL != "" {
+			sb.WriteString(step.SQL + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,191 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/n0xum/structify/internal/domain/entity"
+	"github.com/n0xum/structify/internal/mapper"
+)
+
+var ErrFKTableNotFound = errors.New("foreign key references unknown table")
+var ErrFKColumnNotFound = errors.New("foreign key references unknown column")
+var ErrFKTypeMismatch = errors.New("foreign key type does not match referenced column")
+var ErrFKGroupInconsistent = errors.New("composite foreign key references more than one table")
+var ErrDefaultNotInEnum = errors.New("default value is not one of the enum values")
+var ErrIgnoredPrimaryKey = errors.New("primary key field is ignored")
+var ErrFKReferencesView = errors.New("foreign key references a view")
+var ErrPartitionKeyNotFound = errors.New("partition key references unknown column")
+var ErrPartitionKeyNotInPK = errors.New("partition key column is not part of the primary key")
+
+// ValidateModel checks the rules that span fields and entities: foreign key
+// targets must exist among entities with a compatible column type and must
+// not be views, composite foreign keys must reference a single table,
+// defaults must be valid enum values, primary keys must not be ignored and
+// the partition key of a partitioned table must be part of its primary key.
+// All problems are returned together as a joined error.
+func ValidateModel(entities []*entity.Entity) error {
+	m := mapper.NewMapper()
+	var errs []error
+	for _, ent := range entities {
+		errs = append(errs, validatePartitionKey(ent)...)
+
+		groupTables := make(map[string]string)
+		for _, field := range ent.Fields {
+			fail := func(err error, format string, args ...any) {
+				errs = append(errs, fieldError(ent, field, err, format, args...))
+			}
+
+			if field.IsPrimary && field.IsIgnored {
+				fail(ErrIgnoredPrimaryKey, "")
+			}
+
+			if field.DefaultVal != "" && len(field.EnumValues) > 0 && !isEnumValue(field.DefaultVal, field.EnumValues) {
+				fail(ErrDefaultNotInEnum, "%s not in %v", field.DefaultVal, field.EnumValues)
+			}
+
+			ref := field.FKReference
+			if ref == nil {
+				continue
+			}
+
+			if field.FKGroup != "" {
+				if first, ok := groupTables[field.FKGroup]; !ok {
+					groupTables[field.FKGroup] = ref.Table
+				} else if first != ref.Table {
+					fail(ErrFKGroupInconsistent, "%s references %s and %s", field.FKGroup, first, ref.Table)
+				}
+			}
+
+			target := findTable(entities, ref.Table)
+			if target == nil {
+				fail(ErrFKTableNotFound, "%s", ref.Table)
+				continue
+			}
+			if target.View {
+				fail(ErrFKReferencesView, "%s", ref.Table)
+				continue
+			}
+			refField := findColumn(target, ref.Column)
+			if refField == nil {
+				fail(ErrFKColumnNotFound, "%s.%s", ref.Table, ref.Column)
+				continue
+			}
+
+			fieldType := columnType(m, &field)
+			refType := columnType(m, refField)
+			if !compatibleTypes(fieldType, refType) {
+				fail(ErrFKTypeMismatch, "%s references %s.%s of type %s", fieldType, ref.Table, ref.Column, refType)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validatePartitionKey checks that every partition key column exists and,
+// as PostgreSQL requires, belongs to the primary key when there is one.
+func validatePartitionKey(ent *entity.Entity) []error {
+	if ent.PartitionBy == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, column := range ent.PartitionBy.Columns {
+		field := findColumn(ent, column)
+		switch {
+		case field == nil:
+			errs = append(errs, fmt.Errorf("%s: %w: %s", ent.Name, ErrPartitionKeyNotFound, column))
+		case ent.HasPrimaryKey() && !field.IsPrimary:
+			errs = append(errs, fieldError(ent, *field, ErrPartitionKeyNotInPK, ""))
+		}
+	}
+	return errs
+}
+
+// fieldError prefixes err with the field's position, when known, and its
+// qualified name, e.g. "order.go:7:2: Order.UserID: ...".
+func fieldError(ent *entity.Entity, field entity.Field, err error, format string, args ...any) error {
+	prefix := ent.Name + "." + field.Name
+	if field.Pos.IsValid() {
+		prefix = field.Pos.String() + ": " + prefix
+	}
+	if format == "" {
+		return fmt.Errorf("%s: %w", prefix, err)
+	}
+	return fmt.Errorf("%s: %w: %s", prefix, err, fmt.Sprintf(format, args...))
+}
+
+// findTable returns the entity whose table is name, bare or qualified with
+// a schema, or nil.
+func findTable(entities []*entity.Entity, name string) *entity.Entity {
+	for _, ent := range entities {
+		if ent.HasTable(name) {
+			return ent
+		}
+	}
+	return nil
+}
+
+// findColumn returns the non-ignored field of ent stored in column.
+func findColumn(ent *entity.Entity, column string) *entity.Field {
+	for i := range ent.Fields {
+		if !ent.Fields[i].IsIgnored && ent.Fields[i].ColumnName() == column {
+			return &ent.Fields[i]
+		}
+	}
+	return nil
+}
+
+// isEnumValue reports whether a DEFAULT value, optionally written as a
+// quoted SQL literal, is one of the enum values.
+func isEnumValue(defaultVal string, enumValues []string) bool {
+	value := defaultVal
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	for _, v := range enumValues {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// typeFamilies groups PostgreSQL types that can reference each other.
+var typeFamilies = map[string]string{
+	"SMALLINT":  "integer",
+	"INTEGER":   "integer",
+	"BIGINT":    "integer",
+	"SERIAL":    "integer",
+	"BIGSERIAL": "integer",
+	"TEXT":      "text",
+	"VARCHAR":   "text",
+	"CHAR":      "text",
+}
+
+// compatibleTypes reports whether a foreign key column of type a may
+// reference a column of type b.
+func compatibleTypes(a, b string) bool {
+	a, b = baseSQLType(a), baseSQLType(b)
+	if a == b {
+		return true
+	}
+	family, ok := typeFamilies[a]
+	return ok && family == typeFamilies[b]
+}
+
+// columnType returns the SQL type of field's column, honoring type:,
+// size: and precision: overrides.
+func columnType(m *mapper.Mapper, field *entity.Field) string {
+	mapping := m.MapFieldType(field.Type, field.UnderlyingType)
+	return m.ColumnType(mapping, mapper.TypeTags(field.SQLType, field.Size, field.Precision, field.Scale))
+}
+
+// baseSQLType strips type modifiers: VARCHAR(255) becomes VARCHAR.
+func baseSQLType(sqlType string) string {
+	if i := strings.Index(sqlType, "("); i >= 0 {
+		sqlType = sqlType[:i]
+	}
+	return strings.ToUpper(strings.TrimSpace(sqlType))
+}
@@ -0,0 +1,230 @@
+package validator
+
+import (
+	"errors"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/n0xum/structify/internal/domain/entity"
+)
+
+func TestValidateModel(t *testing.T) {
+	user := &entity.Entity{
+		Name: "User",
+		Fields: []entity.Field{
+			{Name: "ID", Type: "int64", IsPrimary: true},
+			{Name: "Email", Type: "string"},
+			{Name: "Secret", Type: "string", IsIgnored: true},
+		},
+	}
+	region := &entity.Entity{
+		Name:      "Region",
+		TableName: "regions",
+		Schema:    "geo",
+		Fields: []entity.Field{
+			{Name: "ID", Type: "int32", IsPrimary: true},
+		},
+	}
+
+	stats := &entity.Entity{
+		Name: "UserStats",
+		View: true,
+		Fields: []entity.Field{
+			{Name: "ID", Type: "int64", IsPrimary: true},
+		},
+	}
+
+	fk := func(table, column string) *entity.FKReference {
+		return &entity.FKReference{Table: table, Column: column}
+	}
+
+	tests := []struct {
+		name   string
+		fields []entity.Field
+		want   []error
+	}{
+		{
+			name: "valid references",
+			fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "UserID", Type: "*int64", FKReference: fk("user", "id")},
+				{Name: "RegionID", Type: "int64", FKReference: fk("regions", "id")},
+				{Name: "Status", Type: "string", EnumValues: []string{"new", "done"}, DefaultVal: "'new'"},
+			},
+		},
+		{
+			name: "schema-qualified references",
+			fields: []entity.Field{
+				{Name: "RegionID", Type: "int64", FKReference: fk("geo.regions", "id")},
+				{Name: "ShopRegionID", Type: "int64", FKReference: fk("shop.regions", "id")},
+			},
+			want: []error{ErrFKTableNotFound},
+		},
+		{
+			name: "view reference",
+			fields: []entity.Field{
+				{Name: "StatsID", Type: "int64", FKReference: fk("user_stats", "id")},
+			},
+			want: []error{ErrFKReferencesView},
+		},
+		{
+			name: "unknown table",
+			fields: []entity.Field{
+				{Name: "UserID", Type: "int64", FKReference: fk("users", "id")},
+			},
+			want: []error{ErrFKTableNotFound},
+		},
+		{
+			name: "unknown and ignored columns",
+			fields: []entity.Field{
+				{Name: "UserID", Type: "int64", FKReference: fk("user", "uid")},
+				{Name: "UserSecret", Type: "string", FKReference: fk("user", "secret")},
+			},
+			want: []error{ErrFKColumnNotFound, ErrFKColumnNotFound},
+		},
+		{
+			name: "incompatible types",
+			fields: []entity.Field{
+				{Name: "UserID", Type: "string", FKReference: fk("user", "id")},
+			},
+			want: []error{ErrFKTypeMismatch},
+		},
+		{
+			name: "type override",
+			fields: []entity.Field{
+				{Name: "UserID", Type: "string", SQLType: "BIGINT", FKReference: fk("user", "id")},
+				{Name: "RegionID", Type: "int64", SQLType: "UUID", FKReference: fk("regions", "id")},
+			},
+			want: []error{ErrFKTypeMismatch},
+		},
+		{
+			name: "inconsistent composite group",
+			fields: []entity.Field{
+				{Name: "UserID", Type: "int64", FKGroup: "fk_owner", FKReference: fk("user", "id")},
+				{Name: "RegionID", Type: "int64", FKGroup: "fk_owner", FKReference: fk("regions", "id")},
+			},
+			want: []error{ErrFKGroupInconsistent},
+		},
+		{
+			name: "default outside enum",
+			fields: []entity.Field{
+				{Name: "Status", Type: "string", EnumValues: []string{"new", "done"}, DefaultVal: "'open'"},
+			},
+			want: []error{ErrDefaultNotInEnum},
+		},
+		{
+			name: "ignored primary key",
+			fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true, IsIgnored: true},
+			},
+			want: []error{ErrIgnoredPrimaryKey},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &entity.Entity{Name: "Order", Fields: tt.fields}
+			err := ValidateModel([]*entity.Entity{user, region, stats, order})
+
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("ValidateModel() error = %v, want nil", err)
+				}
+				return
+			}
+			for _, want := range tt.want {
+				if !errors.Is(err, want) {
+					t.Errorf("ValidateModel() error = %v, want %v", err, want)
+				}
+			}
+			if got := len(err.(interface{ Unwrap() []error }).Unwrap()); got != len(tt.want) {
+				t.Errorf("ValidateModel() returned %d errors, want %d: %v", got, len(tt.want), err)
+			}
+		})
+	}
+}
+
+func TestValidateModelPartitionKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		columns []string
+		fields  []entity.Field
+		want    []error
+	}{
+		{
+			name:    "key in primary key",
+			columns: []string{"created_at"},
+			fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "CreatedAt", Type: "time.Time", IsPrimary: true},
+			},
+		},
+		{
+			name:    "no primary key",
+			columns: []string{"created_at"},
+			fields: []entity.Field{
+				{Name: "CreatedAt", Type: "time.Time"},
+			},
+		},
+		{
+			name:    "key outside primary key",
+			columns: []string{"created_at"},
+			fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "CreatedAt", Type: "time.Time"},
+			},
+			want: []error{ErrPartitionKeyNotInPK},
+		},
+		{
+			name:    "unknown column",
+			columns: []string{"created"},
+			fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+			},
+			want: []error{ErrPartitionKeyNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := &entity.Entity{
+				Name:        "Event",
+				Fields:      tt.fields,
+				PartitionBy: &entity.Partitioning{Method: "RANGE", Columns: tt.columns},
+			}
+			err := ValidateModel([]*entity.Entity{event})
+
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("ValidateModel() error = %v, want nil", err)
+				}
+				return
+			}
+			for _, want := range tt.want {
+				if !errors.Is(err, want) {
+					t.Errorf("ValidateModel() error = %v, want %v", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateModelErrorPosition(t *testing.T) {
+	order := &entity.Entity{
+		Name: "Order",
+		Fields: []entity.Field{
+			{
+				Name:        "UserID",
+				Type:        "int64",
+				FKReference: &entity.FKReference{Table: "users", Column: "id"},
+				Pos:         token.Position{Filename: "order.go", Line: 4, Column: 2},
+			},
+		},
+	}
+
+	err := ValidateModel([]*entity.Entity{order})
+	if err == nil || !strings.HasPrefix(err.Error(), "order.go:4:2: Order.UserID: ") {
+		t.Errorf("ValidateModel() error = %v, want it prefixed with the field position", err)
+	}
+}
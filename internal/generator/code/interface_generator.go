@@ -2,6 +2,7 @@ package code
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -9,9 +10,26 @@ import (
 	"github.com/n0xum/structify/internal/util"
 )
 
+var ErrReadOnlyView = errors.New("view is read-only")
+var ErrNotMaterialized = errors.New("only a materialized view can be refreshed")
+
 // GenerateFromInterface generates a repository implementation from
-// an interface definition and an entity.
+// an interface definition and an entity. Views only support read methods;
+// Refresh requires a materialized view.
 func (g *RepositoryGenerator) GenerateFromInterface(ctx context.Context, packageName string, ent *entity.Entity, repo *entity.RepositoryInterface) (string, error) {
+	for _, method := range repo.Methods {
+		switch method.Kind {
+		case entity.MethodCreate, entity.MethodUpdate, entity.MethodDelete:
+			if ent.View {
+				return "", fmt.Errorf("%s.%s: %w: %s", repo.Name, method.Name, ErrReadOnlyView, ent.Name)
+			}
+		case entity.MethodRefresh:
+			if !ent.Materialized {
+				return "", fmt.Errorf("%s.%s: %w: %s", repo.Name, method.Name, ErrNotMaterialized, ent.Name)
+			}
+		}
+	}
+
 	var sb strings.Builder
 
 	if packageName == "" {
@@ -63,9 +81,18 @@ func (g *RepositoryGenerator) generateInterfaceMethod(sb *strings.Builder, implN
 		g.genSmartQuery(sb, implName, ent, method)
 	case entity.MethodCustomSQL:
 		g.genCustomSQL(sb, implName, ent, method)
+	case entity.MethodRefresh:
+		g.genRefresh(sb, implName, ent, method)
 	}
 }
 
+func (g *RepositoryGenerator) genRefresh(sb *strings.Builder, implName string, ent *entity.Entity, method entity.RepositoryMethod) {
+	sb.WriteString(fmt.Sprintf("func (r *%s) %s(ctx context.Context) error {\n", implName, method.Name))
+	sb.WriteString(fmt.Sprintf("\t_, err := r.db.ExecContext(ctx, `REFRESH MATERIALIZED VIEW %s`)\n", ent.GetQuotedTableName()))
+	sb.WriteString("\treturn err\n")
+	sb.WriteString("}\n\n")
+}
+
 func (g *RepositoryGenerator) genCreate(sb *strings.Builder, implName string, ent *entity.Entity, method entity.RepositoryMethod) {
 	tableName := ent.GetQuotedTableName()
 	fields := ent.GetGenerateableFields()
@@ -77,12 +104,12 @@ func (g *RepositoryGenerator) genCreate(sb *strings.Builder, implName string, en
 	var columns []string
 	var args []string
 	for _, field := range fields {
-		if field.IsPrimary && field.Type == "int64" && !ent.HasCompositePrimaryKey() {
+		if field.IsGeneratedByDatabase() {
 			continue
 		}
-		colName := util.ToSnakeCase(field.Name)
+		colName := field.ColumnName()
 		columns = append(columns, colName)
-		args = append(args, "item."+field.Name)
+		args = append(args, "item."+field.GoSelector())
 	}
 
 	placeholders := make([]string, len(columns))
@@ -93,7 +120,7 @@ func (g *RepositoryGenerator) genCreate(sb *strings.Builder, implName string, en
 	// Build RETURNING clause
 	var returningCols []string
 	for _, field := range fields {
-		returningCols = append(returningCols, util.ToSnakeCase(field.Name))
+		returningCols = append(returningCols, field.ColumnName())
 	}
 
 	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s",
@@ -103,21 +130,24 @@ func (g *RepositoryGenerator) genCreate(sb *strings.Builder, implName string, en
 		strings.Join(returningCols, ", "))
 
 	sb.WriteString(fmt.Sprintf("\tquery := `%s`\n", query))
+
+	var scan rowScan
+	for _, field := range fields {
+		g.scanInto(&scan, "result."+field.GoSelector(), field)
+	}
+
 	sb.WriteString(fmt.Sprintf("\tvar result %s\n", ent.Name))
+	scan.writeDecls(sb, "\t")
 	sb.WriteString("\terr := r.db.QueryRowContext(ctx, query, ")
 	sb.WriteString(strings.Join(args, ", "))
 	sb.WriteString(").Scan(")
-
-	var scanFields []string
-	for _, field := range fields {
-		scanFields = append(scanFields, "&result."+field.Name)
-	}
-	sb.WriteString(strings.Join(scanFields, ", "))
+	sb.WriteString(scan.args())
 	sb.WriteString(")\n")
 
 	sb.WriteString("\tif err != nil {\n")
 	sb.WriteString("\t\treturn nil, err\n")
 	sb.WriteString("\t}\n")
+	scan.writeAssigns(sb, "\t")
 	sb.WriteString("\treturn &result, nil\n")
 	sb.WriteString("}\n\n")
 }
@@ -134,21 +164,28 @@ func (g *RepositoryGenerator) genGetByID(sb *strings.Builder, implName string, e
 
 	var columns []string
 	for _, field := range fields {
-		columns = append(columns, util.ToSnakeCase(field.Name))
+		columns = append(columns, field.ColumnName())
 	}
 
 	// WHERE clause from PK fields
 	pkFields := ent.GetPrimaryKeyFields()
 	var whereParts []string
 	for i, pk := range pkFields {
-		whereParts = append(whereParts, fmt.Sprintf("%s = $%d", util.ToSnakeCase(pk.Name), i+1))
+		whereParts = append(whereParts, fmt.Sprintf("%s = $%d", pk.ColumnName(), i+1))
 	}
 
 	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s",
 		strings.Join(columns, ", "), tableName, strings.Join(whereParts, " AND "))
 
 	sb.WriteString(fmt.Sprintf("\tquery := `%s`\n", query))
+
+	var scan rowScan
+	for _, field := range fields {
+		g.scanInto(&scan, "item."+field.GoSelector(), field)
+	}
+
 	sb.WriteString(fmt.Sprintf("\tvar item %s\n", ent.Name))
+	scan.writeDecls(sb, "\t")
 	sb.WriteString("\terr := r.db.QueryRowContext(ctx, query, ")
 
 	var paramNames []string
@@ -157,17 +194,13 @@ func (g *RepositoryGenerator) genGetByID(sb *strings.Builder, implName string, e
 	}
 	sb.WriteString(strings.Join(paramNames, ", "))
 	sb.WriteString(").Scan(")
-
-	var scanFields []string
-	for _, field := range fields {
-		scanFields = append(scanFields, "&item."+field.Name)
-	}
-	sb.WriteString(strings.Join(scanFields, ", "))
+	sb.WriteString(scan.args())
 	sb.WriteString(")\n")
 
 	sb.WriteString("\tif err != nil {\n")
 	sb.WriteString("\t\treturn nil, err\n")
 	sb.WriteString("\t}\n")
+	scan.writeAssigns(sb, "\t")
 	sb.WriteString("\treturn &item, nil\n")
 	sb.WriteString("}\n\n")
 }
@@ -182,20 +215,20 @@ func (g *RepositoryGenerator) genUpdate(sb *strings.Builder, implName string, en
 	var updates []string
 	var args []string
 	for _, field := range fields {
-		if field.IsPrimary {
+		if field.IsPrimary || field.IsGeneratedByDatabase() {
 			continue
 		}
-		colName := util.ToSnakeCase(field.Name)
+		colName := field.ColumnName()
 		updates = append(updates, fmt.Sprintf("%s = $%d", colName, len(updates)+1))
-		args = append(args, "item."+field.Name)
+		args = append(args, "item."+field.GoSelector())
 	}
 
 	pkFields := ent.GetPrimaryKeyFields()
 	var whereParts []string
 	for i, pk := range pkFields {
-		colName := util.ToSnakeCase(pk.Name)
+		colName := pk.ColumnName()
 		whereParts = append(whereParts, fmt.Sprintf("%s = $%d", colName, len(updates)+i+1))
-		args = append(args, "item."+pk.Name)
+		args = append(args, "item."+pk.GoSelector())
 	}
 
 	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s",
@@ -220,7 +253,7 @@ func (g *RepositoryGenerator) genDelete(sb *strings.Builder, implName string, en
 	pkFields := ent.GetPrimaryKeyFields()
 	var whereParts []string
 	for i, pk := range pkFields {
-		whereParts = append(whereParts, fmt.Sprintf("%s = $%d", util.ToSnakeCase(pk.Name), i+1))
+		whereParts = append(whereParts, fmt.Sprintf("%s = $%d", pk.ColumnName(), i+1))
 	}
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE %s", tableName, strings.Join(whereParts, " AND "))
@@ -247,13 +280,13 @@ func (g *RepositoryGenerator) genList(sb *strings.Builder, implName string, ent
 
 	var columns []string
 	for _, field := range fields {
-		columns = append(columns, util.ToSnakeCase(field.Name))
+		columns = append(columns, field.ColumnName())
 	}
 
 	pkFields := ent.GetPrimaryKeyFields()
 	var orderBy []string
 	for _, pk := range pkFields {
-		orderBy = append(orderBy, util.ToSnakeCase(pk.Name))
+		orderBy = append(orderBy, pk.ColumnName())
 	}
 	orderClause := "id"
 	if len(orderBy) > 0 {
@@ -283,13 +316,16 @@ func (g *RepositoryGenerator) genFindBy(sb *strings.Builder, implName string, en
 
 	var columns []string
 	for _, field := range fields {
-		columns = append(columns, util.ToSnakeCase(field.Name))
+		columns = append(columns, field.ColumnName())
 	}
 
 	// WHERE clause from FindBy fields
 	var whereParts []string
 	for i, fieldName := range method.FindByFields {
 		colName := util.ToSnakeCase(fieldName)
+		if field := ent.FieldByName(fieldName); field != nil {
+			colName = field.ColumnName()
+		}
 		whereParts = append(whereParts, fmt.Sprintf("%s = $%d", colName, i+1))
 	}
 
@@ -304,20 +340,22 @@ func (g *RepositoryGenerator) genFindBy(sb *strings.Builder, implName string, en
 	}
 
 	if method.ReturnsSingle {
+		var scan rowScan
+		for _, field := range fields {
+			g.scanInto(&scan, "item."+field.GoSelector(), field)
+		}
+
 		sb.WriteString(fmt.Sprintf("\tvar item %s\n", ent.Name))
+		scan.writeDecls(sb, "\t")
 		sb.WriteString("\terr := r.db.QueryRowContext(ctx, query, ")
 		sb.WriteString(strings.Join(paramNames, ", "))
 		sb.WriteString(").Scan(")
-
-		var scanFields []string
-		for _, field := range fields {
-			scanFields = append(scanFields, "&item."+field.Name)
-		}
-		sb.WriteString(strings.Join(scanFields, ", "))
+		sb.WriteString(scan.args())
 		sb.WriteString(")\n")
 		sb.WriteString("\tif err != nil {\n")
 		sb.WriteString("\t\treturn nil, err\n")
 		sb.WriteString("\t}\n")
+		scan.writeAssigns(sb, "\t")
 		sb.WriteString("\treturn &item, nil\n")
 		sb.WriteString("}\n\n")
 	} else {
@@ -379,20 +417,22 @@ func (g *RepositoryGenerator) genCustomSQL(sb *strings.Builder, implName string,
 	fields := ent.GetGenerateableFields()
 
 	if method.ReturnsSingle {
+		var scan rowScan
+		for _, field := range fields {
+			g.scanInto(&scan, "item."+field.GoSelector(), field)
+		}
+
 		sb.WriteString(fmt.Sprintf("\tvar item %s\n", ent.Name))
+		scan.writeDecls(sb, "\t")
 		sb.WriteString("\terr := r.db.QueryRowContext(ctx, query, ")
 		sb.WriteString(strings.Join(paramNames, ", "))
 		sb.WriteString(").Scan(")
-
-		var scanFields []string
-		for _, field := range fields {
-			scanFields = append(scanFields, "&item."+field.Name)
-		}
-		sb.WriteString(strings.Join(scanFields, ", "))
+		sb.WriteString(scan.args())
 		sb.WriteString(")\n")
 		sb.WriteString("\tif err != nil {\n")
 		sb.WriteString("\t\treturn nil, err\n")
 		sb.WriteString("\t}\n")
+		scan.writeAssigns(sb, "\t")
 		sb.WriteString("\treturn &item, nil\n")
 		sb.WriteString("}\n\n")
 	} else {
@@ -458,23 +498,25 @@ func (g *RepositoryGenerator) genSmartQuery(sb *strings.Builder, implName string
 	fields := ent.GetGenerateableFields()
 
 	if method.ReturnsSingle {
+		var scan rowScan
+		for _, field := range fields {
+			g.scanInto(&scan, "item."+field.GoSelector(), field)
+		}
+
 		sb.WriteString(fmt.Sprintf("\tvar item %s\n", ent.Name))
+		scan.writeDecls(sb, "\t")
 		sb.WriteString("\terr := r.db.QueryRowContext(ctx, query")
 		if len(paramNames) > 0 {
 			sb.WriteString(", ")
 			sb.WriteString(strings.Join(paramNames, ", "))
 		}
 		sb.WriteString(").Scan(")
-
-		var scanFields []string
-		for _, field := range fields {
-			scanFields = append(scanFields, "&item."+field.Name)
-		}
-		sb.WriteString(strings.Join(scanFields, ", "))
+		sb.WriteString(scan.args())
 		sb.WriteString(")\n")
 		sb.WriteString("\tif err != nil {\n")
 		sb.WriteString("\t\treturn nil, err\n")
 		sb.WriteString("\t}\n")
+		scan.writeAssigns(sb, "\t")
 		sb.WriteString("\treturn &item, nil\n")
 		sb.WriteString("}\n\n")
 	} else {
@@ -499,16 +541,18 @@ func (g *RepositoryGenerator) writeRowsLoop(sb *strings.Builder, ent *entity.Ent
 
 	sb.WriteString(fmt.Sprintf("\tvar items []*%s\n", ent.Name))
 	sb.WriteString("\tfor rows.Next() {\n")
-	sb.WriteString(fmt.Sprintf("\t\tvar item %s\n", ent.Name))
 
-	var scanFields []string
+	var scan rowScan
 	for _, field := range fields {
-		scanFields = append(scanFields, "&item."+field.Name)
+		g.scanInto(&scan, "item."+field.GoSelector(), field)
 	}
 
-	sb.WriteString(fmt.Sprintf("\t\tif err := rows.Scan(%s); err != nil {\n", strings.Join(scanFields, ", ")))
+	sb.WriteString(fmt.Sprintf("\t\tvar item %s\n", ent.Name))
+	scan.writeDecls(sb, "\t\t")
+	sb.WriteString(fmt.Sprintf("\t\tif err := rows.Scan(%s); err != nil {\n", scan.args()))
 	sb.WriteString("\t\t\treturn nil, err\n")
 	sb.WriteString("\t\t}\n")
+	scan.writeAssigns(sb, "\t\t")
 	sb.WriteString("\t\titems = append(items, &item)\n")
 	sb.WriteString("\t}\n")
 	sb.WriteString("\tif err := rows.Err(); err != nil {\n")
@@ -529,16 +573,18 @@ func (g *RepositoryGenerator) writeRowsLoopWithParams(sb *strings.Builder, ent *
 
 	sb.WriteString(fmt.Sprintf("\tvar items []*%s\n", ent.Name))
 	sb.WriteString("\tfor rows.Next() {\n")
-	sb.WriteString(fmt.Sprintf("\t\tvar item %s\n", ent.Name))
 
-	var scanFields []string
+	var scan rowScan
 	for _, field := range fields {
-		scanFields = append(scanFields, "&item."+field.Name)
+		g.scanInto(&scan, "item."+field.GoSelector(), field)
 	}
 
-	sb.WriteString(fmt.Sprintf("\t\tif err := rows.Scan(%s); err != nil {\n", strings.Join(scanFields, ", ")))
+	sb.WriteString(fmt.Sprintf("\t\tvar item %s\n", ent.Name))
+	scan.writeDecls(sb, "\t\t")
+	sb.WriteString(fmt.Sprintf("\t\tif err := rows.Scan(%s); err != nil {\n", scan.args()))
 	sb.WriteString("\t\t\treturn nil, err\n")
 	sb.WriteString("\t\t}\n")
+	scan.writeAssigns(sb, "\t\t")
 	sb.WriteString("\t\titems = append(items, &item)\n")
 	sb.WriteString("\t}\n")
 	sb.WriteString("\tif err := rows.Err(); err != nil {\n")
@@ -46,6 +46,12 @@ func (g *RepositoryGenerator) generateRepository(sb *strings.Builder, ent *entit
 	generateableFields := ent.GetGenerateableFields()
 
 	g.generateEntityStruct(sb, ent, generateableFields)
+
+	if ent.View {
+		g.generateViewRepository(sb, ent, generateableFields)
+		return
+	}
+
 	g.generateCreateMethod(sb, ent, generateableFields)
 
 	// Generate different CRUD methods based on PK type
@@ -65,6 +71,31 @@ func (g *RepositoryGenerator) generateRepository(sb *strings.Builder, ent *entit
 	g.generateJoinMethods(sb, ent, allEntities)
 }
 
+// generateViewRepository generates the read methods of a view, and a
+// Refresh method for a materialized view. GetByID needs a pk field to look
+// rows up by.
+func (g *RepositoryGenerator) generateViewRepository(sb *strings.Builder, ent *entity.Entity, fields []entity.Field) {
+	switch {
+	case ent.HasCompositePrimaryKey():
+		g.generateGetByCompositePKMethod(sb, ent, fields)
+	case ent.HasPrimaryKey():
+		g.generateGetByIDMethod(sb, ent, fields)
+	}
+
+	g.generateListMethod(sb, ent, fields)
+
+	if ent.Materialized {
+		g.generateRefreshMethod(sb, ent)
+	}
+}
+
+func (g *RepositoryGenerator) generateRefreshMethod(sb *strings.Builder, ent *entity.Entity) {
+	sb.WriteString(fmt.Sprintf("func Refresh%s(ctx context.Context, db *sql.DB) error {\n", ent.Name))
+	sb.WriteString(fmt.Sprintf("    _, err := db.ExecContext(ctx, `REFRESH MATERIALIZED VIEW %s`)\n", ent.GetQualifiedTableName()))
+	sb.WriteString("    return err\n")
+	sb.WriteString("}\n\n")
+}
+
 func (g *RepositoryGenerator) generateEntityStruct(sb *strings.Builder, ent *entity.Entity, fields []entity.Field) {
 	sb.WriteString(fmt.Sprintf("type %s struct {\n", ent.Name))
 
@@ -76,7 +107,7 @@ func (g *RepositoryGenerator) generateEntityStruct(sb *strings.Builder, ent *ent
 }
 
 func (g *RepositoryGenerator) generateCreateMethod(sb *strings.Builder, ent *entity.Entity, fields []entity.Field) {
-	tableName := ent.GetTableName()
+	tableName := ent.GetQualifiedTableName()
 
 	sb.WriteString(fmt.Sprintf("func Create%s(ctx context.Context, db *sql.DB, item *%s) (*%s, error) {\n", ent.Name, ent.Name, ent.Name))
 
@@ -84,14 +115,14 @@ func (g *RepositoryGenerator) generateCreateMethod(sb *strings.Builder, ent *ent
 	var args []string
 
 	for _, field := range fields {
-		// Skip auto-increment int64 PK fields
-		if field.IsPrimary && field.Type == "int64" && !ent.HasCompositePrimaryKey() {
+		// Skip identity and serial columns, the database generates them
+		if field.IsGeneratedByDatabase() {
 			continue
 		}
 		if !field.ShouldGenerate() {
 			continue
 		}
-		colName := util.ToSnakeCase(field.Name)
+		colName := field.ColumnName()
 		columns = append(columns, colName)
 		args = append(args, fmt.Sprintf("item.%s", field.Name))
 	}
@@ -104,16 +135,17 @@ func (g *RepositoryGenerator) generateCreateMethod(sb *strings.Builder, ent *ent
 	var query string
 	pkFields := ent.GetPrimaryKeyFields()
 	if len(pkFields) == 1 && pkFields[0].Type == "int64" {
-		// Single int64 PK - use RETURNING id
-		query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING id",
+		// Single int64 PK - return it for the lookup by ID
+		query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s",
 			tableName,
 			strings.Join(columns, ", "),
-			strings.Join(placeholders, ", "))
+			strings.Join(placeholders, ", "),
+			g.primaryKeyColumn(ent))
 	} else {
 		// Composite PK or non-int64 PK - use RETURNING all PK columns
 		var returningColumns []string
 		for _, pkField := range pkFields {
-			returningColumns = append(returningColumns, util.ToSnakeCase(pkField.Name))
+			returningColumns = append(returningColumns, pkField.ColumnName())
 		}
 		query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s",
 			tableName,
@@ -176,7 +208,7 @@ func (g *RepositoryGenerator) generateCreateMethod(sb *strings.Builder, ent *ent
 }
 
 func (g *RepositoryGenerator) generateGetByIDMethod(sb *strings.Builder, ent *entity.Entity, fields []entity.Field) {
-	tableName := ent.GetTableName()
+	tableName := ent.GetQualifiedTableName()
 
 	sb.WriteString(fmt.Sprintf("func Get%sByID(ctx context.Context, db *sql.DB, id int64) (*%s, error) {\n", ent.Name, ent.Name))
 
@@ -185,35 +217,37 @@ func (g *RepositoryGenerator) generateGetByIDMethod(sb *strings.Builder, ent *en
 		if !field.ShouldGenerate() {
 			continue
 		}
-		colName := util.ToSnakeCase(field.Name)
+		colName := field.ColumnName()
 		columns = append(columns, colName)
 	}
 
-	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1", strings.Join(columns, ", "), tableName)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1", strings.Join(columns, ", "), tableName, g.primaryKeyColumn(ent))
 	sb.WriteString(fmt.Sprintf("    query := `%s`\n", query))
 
-	sb.WriteString(fmt.Sprintf("    var item %s\n", ent.Name))
-	sb.WriteString("    err := db.QueryRowContext(ctx, query, id).Scan(")
-
-	var scanFields []string
+	var scan rowScan
 	for _, field := range fields {
 		if !field.ShouldGenerate() {
 			continue
 		}
-		scanFields = append(scanFields, fmt.Sprintf("&item.%s", field.Name))
+		g.scanInto(&scan, "item."+field.Name, field)
 	}
-	sb.WriteString(strings.Join(scanFields, ", "))
+
+	sb.WriteString(fmt.Sprintf("    var item %s\n", ent.Name))
+	scan.writeDecls(sb, "    ")
+	sb.WriteString("    err := db.QueryRowContext(ctx, query, id).Scan(")
+	sb.WriteString(scan.args())
 	sb.WriteString(")\n")
 
 	sb.WriteString("    if err != nil {\n")
 	sb.WriteString("        return nil, err\n")
 	sb.WriteString("    }\n")
+	scan.writeAssigns(sb, "    ")
 	sb.WriteString("    return &item, nil\n")
 	sb.WriteString("}\n\n")
 }
 
 func (g *RepositoryGenerator) generateUpdateMethod(sb *strings.Builder, ent *entity.Entity, fields []entity.Field) {
-	tableName := ent.GetTableName()
+	tableName := ent.GetQualifiedTableName()
 
 	sb.WriteString(fmt.Sprintf("func Update%s(ctx context.Context, db *sql.DB, item *%s) error {\n", ent.Name, ent.Name))
 
@@ -224,10 +258,10 @@ func (g *RepositoryGenerator) generateUpdateMethod(sb *strings.Builder, ent *ent
 		if !field.ShouldGenerate() {
 			continue
 		}
-		if field.IsPrimary {
+		if field.IsPrimary || field.IsGeneratedByDatabase() {
 			continue
 		}
-		colName := util.ToSnakeCase(field.Name)
+		colName := field.ColumnName()
 		updates = append(updates, fmt.Sprintf("%s = $%d", colName, len(updates)+1))
 		args = append(args, fmt.Sprintf("item.%s", field.Name))
 	}
@@ -236,7 +270,7 @@ func (g *RepositoryGenerator) generateUpdateMethod(sb *strings.Builder, ent *ent
 	var whereClause []string
 	var whereArgs []string
 	for i, pkField := range pkFields {
-		colName := util.ToSnakeCase(pkField.Name)
+		colName := pkField.ColumnName()
 		whereClause = append(whereClause, fmt.Sprintf("%s = $%d", colName, len(updates)+i+1))
 		whereArgs = append(whereArgs, fmt.Sprintf("item.%s", pkField.Name))
 	}
@@ -262,11 +296,11 @@ func (g *RepositoryGenerator) generateUpdateMethod(sb *strings.Builder, ent *ent
 }
 
 func (g *RepositoryGenerator) generateDeleteMethod(sb *strings.Builder, ent *entity.Entity, fields []entity.Field) {
-	tableName := ent.GetTableName()
+	tableName := ent.GetQualifiedTableName()
 
 	sb.WriteString(fmt.Sprintf("func Delete%s(ctx context.Context, db *sql.DB, id int64) error {\n", ent.Name))
 
-	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", tableName)
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1", tableName, g.primaryKeyColumn(ent))
 	sb.WriteString(fmt.Sprintf("    query := `%s`\n", query))
 
 	sb.WriteString("    _, err := db.ExecContext(ctx, query, id)\n")
@@ -275,7 +309,7 @@ func (g *RepositoryGenerator) generateDeleteMethod(sb *strings.Builder, ent *ent
 }
 
 func (g *RepositoryGenerator) generateListMethod(sb *strings.Builder, ent *entity.Entity, fields []entity.Field) {
-	tableName := ent.GetTableName()
+	tableName := ent.GetQualifiedTableName()
 
 	sb.WriteString(fmt.Sprintf("func List%s(ctx context.Context, db *sql.DB) ([]*%s, error) {\n", ent.Name, ent.Name))
 
@@ -284,7 +318,7 @@ func (g *RepositoryGenerator) generateListMethod(sb *strings.Builder, ent *entit
 		if !field.ShouldGenerate() {
 			continue
 		}
-		colName := util.ToSnakeCase(field.Name)
+		colName := field.ColumnName()
 		columns = append(columns, colName)
 	}
 
@@ -292,7 +326,7 @@ func (g *RepositoryGenerator) generateListMethod(sb *strings.Builder, ent *entit
 	var orderBy []string
 	pkFields := ent.GetPrimaryKeyFields()
 	for _, pkField := range pkFields {
-		orderBy = append(orderBy, util.ToSnakeCase(pkField.Name))
+		orderBy = append(orderBy, pkField.ColumnName())
 	}
 	orderClause := "id"
 	if len(orderBy) > 0 {
@@ -311,18 +345,21 @@ func (g *RepositoryGenerator) generateListMethod(sb *strings.Builder, ent *entit
 	sb.WriteString(fmt.Sprintf("    var items []*%s\n", ent.Name))
 
 	sb.WriteString("    for rows.Next() {\n")
-	sb.WriteString(fmt.Sprintf("        var item %s\n", ent.Name))
 
-	var scanFields []string
+	var scan rowScan
 	for _, field := range fields {
 		if !field.ShouldGenerate() {
 			continue
 		}
-		scanFields = append(scanFields, fmt.Sprintf("&item.%s", field.Name))
+		g.scanInto(&scan, "item."+field.Name, field)
 	}
-	sb.WriteString(fmt.Sprintf("        if err := rows.Scan(%s); err != nil {\n", strings.Join(scanFields, ", ")))
+
+	sb.WriteString(fmt.Sprintf("        var item %s\n", ent.Name))
+	scan.writeDecls(sb, "        ")
+	sb.WriteString(fmt.Sprintf("        if err := rows.Scan(%s); err != nil {\n", scan.args()))
 	sb.WriteString("            return nil, err\n")
 	sb.WriteString("        }\n")
+	scan.writeAssigns(sb, "        ")
 	sb.WriteString("        items = append(items, &item)\n")
 	sb.WriteString("    }\n")
 	sb.WriteString("    return items, nil\n")
@@ -376,7 +413,7 @@ func (g *RepositoryGenerator) groupFKsByTable(fkFields []entity.Field) map[strin
 // findEntity finds an entity by table name
 func (g *RepositoryGenerator) findEntity(tableName string, entities []*entity.Entity) *entity.Entity {
 	for _, ent := range entities {
-		if ent.GetTableName() == tableName {
+		if ent.HasTable(tableName) {
 			return ent
 		}
 	}
@@ -395,8 +432,8 @@ func (g *RepositoryGenerator) generateSingleJoinMethod(sb *strings.Builder, ent,
 		ent.Name, relatedEnt.Name, util.ToSnakeCase(ent.Name), resultStructName))
 
 	// Build JOIN query
-	tableName := ent.GetTableName()
-	relatedTableName := relatedEnt.GetTableName()
+	tableName := ent.GetQualifiedTableName()
+	relatedTableName := relatedEnt.GetQualifiedTableName()
 
 	var columns []string
 	entColumns := g.getEntityColumns(ent)
@@ -410,38 +447,40 @@ func (g *RepositoryGenerator) generateSingleJoinMethod(sb *strings.Builder, ent,
 		columns = append(columns, fmt.Sprintf("%s.%s", relatedTableName, col))
 	}
 
-	query := fmt.Sprintf("SELECT %s FROM %s JOIN %s ON %s.%s = %s.%s WHERE %s.id = $1",
+	query := fmt.Sprintf("SELECT %s FROM %s JOIN %s ON %s.%s = %s.%s WHERE %s.%s = $1",
 		strings.Join(columns, ", "),
 		tableName,
 		relatedTableName,
-		tableName, util.ToSnakeCase(fkField.Name),
+		tableName, fkField.ColumnName(),
 		relatedTableName, fkField.FKReference.Column,
-		tableName)
+		tableName, g.primaryKeyColumn(ent))
 
 	sb.WriteString(fmt.Sprintf("    query := `%s`\n", query))
 
 	// Scan into result struct
-	var scanFields []string
+	var scan rowScan
 	entFields := ent.GetGenerateableFields()
 	relatedFields := relatedEnt.GetGenerateableFields()
 
 	for _, field := range entFields {
-		scanFields = append(scanFields, fmt.Sprintf("&result.%s", field.Name))
+		g.scanInto(&scan, "result."+field.Name, field)
 	}
 	for _, field := range relatedFields {
-		scanFields = append(scanFields, fmt.Sprintf("&result.%s", field.Name))
+		g.scanInto(&scan, "result."+field.Name, field)
 	}
 
 	sb.WriteString(fmt.Sprintf("    var result %s\n", resultStructName))
+	scan.writeDecls(sb, "    ")
 	sb.WriteString("    err := db.QueryRowContext(ctx, query, ")
 	sb.WriteString(fmt.Sprintf("%sID", util.ToSnakeCase(ent.Name)))
 	sb.WriteString(").Scan(")
-	sb.WriteString(strings.Join(scanFields, ", "))
+	sb.WriteString(scan.args())
 	sb.WriteString(")\n")
 
 	sb.WriteString("    if err != nil {\n")
 	sb.WriteString("        return nil, err\n")
 	sb.WriteString("    }\n")
+	scan.writeAssigns(sb, "    ")
 	sb.WriteString("    return &result, nil\n")
 	sb.WriteString("}\n\n")
 }
@@ -473,7 +512,7 @@ func (g *RepositoryGenerator) generateMultiJoinMethod(sb *strings.Builder, ent *
 		ent.Name, resultStructName))
 
 	// Build JOIN query with multiple tables
-	tableName := ent.GetTableName()
+	tableName := ent.GetQualifiedTableName()
 
 	var columns []string
 	var joins []string
@@ -486,7 +525,7 @@ func (g *RepositoryGenerator) generateMultiJoinMethod(sb *strings.Builder, ent *
 
 	// Add JOINs and related entity columns
 	for _, relatedEnt := range relatedEntities {
-		relatedTableName := relatedEnt.GetTableName()
+		relatedTableName := relatedEnt.GetQualifiedTableName()
 		relatedColumns := g.getEntityColumns(relatedEnt)
 
 		for _, col := range relatedColumns {
@@ -496,13 +535,13 @@ func (g *RepositoryGenerator) generateMultiJoinMethod(sb *strings.Builder, ent *
 
 	// Build JOIN clauses
 	for _, relatedEnt := range relatedEntities {
-		relatedTableName := relatedEnt.GetTableName()
+		relatedTableName := relatedEnt.GetQualifiedTableName()
 		// Find FK field that references this table
 		for _, fk := range ent.Fields {
-			if fk.FKReference != nil && fk.FKReference.Table == relatedTableName && fk.ShouldGenerate() {
+			if fk.FKReference != nil && relatedEnt.HasTable(fk.FKReference.Table) && fk.ShouldGenerate() {
 				joins = append(joins, fmt.Sprintf("JOIN %s ON %s.%s = %s.%s",
 					relatedTableName,
-					tableName, util.ToSnakeCase(fk.Name),
+					tableName, fk.ColumnName(),
 					relatedTableName, fk.FKReference.Column))
 				break
 			}
@@ -510,39 +549,118 @@ func (g *RepositoryGenerator) generateMultiJoinMethod(sb *strings.Builder, ent *
 	}
 
 	joinClause := strings.Join(joins, " ")
-	query := fmt.Sprintf("SELECT %s FROM %s %s WHERE %s.id = $1",
+	query := fmt.Sprintf("SELECT %s FROM %s %s WHERE %s.%s = $1",
 		strings.Join(columns, ", "),
 		tableName,
 		joinClause,
-		tableName)
+		tableName, g.primaryKeyColumn(ent))
 
 	sb.WriteString(fmt.Sprintf("    query := `%s`\n", query))
 
 	// Scan into result struct
-	var scanFields []string
+	var scan rowScan
 	entFields := ent.GetGenerateableFields()
 	for _, field := range entFields {
-		scanFields = append(scanFields, fmt.Sprintf("&result.%s", field.Name))
+		g.scanInto(&scan, "result."+field.Name, field)
 	}
 	for _, relatedEnt := range relatedEntities {
 		relatedFields := relatedEnt.GetGenerateableFields()
 		for _, field := range relatedFields {
-			scanFields = append(scanFields, fmt.Sprintf("&result.%s", field.Name))
+			g.scanInto(&scan, "result."+field.Name, field)
 		}
 	}
 
 	sb.WriteString(fmt.Sprintf("    var result %s\n", resultStructName))
+	scan.writeDecls(sb, "    ")
 	sb.WriteString("    err := db.QueryRowContext(ctx, query, id).Scan(")
-	sb.WriteString(strings.Join(scanFields, ", "))
+	sb.WriteString(scan.args())
 	sb.WriteString(")\n")
 
 	sb.WriteString("    if err != nil {\n")
 	sb.WriteString("        return nil, err\n")
 	sb.WriteString("    }\n")
+	scan.writeAssigns(sb, "    ")
 	sb.WriteString("    return &result, nil\n")
 	sb.WriteString("}\n\n")
 }
 
+// primaryKeyColumn returns the column of the entity's single primary key,
+// falling back to "id" when there is none
+func (g *RepositoryGenerator) primaryKeyColumn(ent *entity.Entity) string {
+	if pk := ent.GetPrimaryKeyField(); pk != nil {
+		return pk.ColumnName()
+	}
+	return "id"
+}
+
+// rowScan collects the Scan destinations of one row. A column that may be
+// NULL while its field's Go type cannot hold NULL is scanned into a local
+// sql.Null[T] and copied into the field after the Scan, so that generated
+// files declare no helpers that would clash within a package.
+type rowScan struct {
+	dests   []string
+	decls   []string
+	assigns []string
+	names   map[string]bool
+}
+
+// scanInto adds the Scan destination for field stored at expr.
+func (g *RepositoryGenerator) scanInto(scan *rowScan, expr string, field entity.Field) {
+	if !g.needsNullable(field) {
+		scan.dests = append(scan.dests, "&"+expr)
+		return
+	}
+	if scan.names == nil {
+		scan.names = make(map[string]bool)
+	}
+	_, selector, _ := strings.Cut(expr, ".")
+	name := "null" + strings.ReplaceAll(selector, ".", "")
+	for i := 2; scan.names[name]; i++ {
+		name = fmt.Sprintf("null%s%d", strings.ReplaceAll(selector, ".", ""), i)
+	}
+	scan.names[name] = true
+	scan.dests = append(scan.dests, "&"+name)
+	scan.decls = append(scan.decls, fmt.Sprintf("var %s sql.Null[%s]", name, field.Type))
+	scan.assigns = append(scan.assigns, fmt.Sprintf("%s = %s.V", expr, name))
+}
+
+// args returns the destinations as the arguments of Scan.
+func (s *rowScan) args() string {
+	return strings.Join(s.dests, ", ")
+}
+
+// writeDecls declares the sql.Null variables, before the Scan.
+func (s *rowScan) writeDecls(sb *strings.Builder, indent string) {
+	for _, decl := range s.decls {
+		sb.WriteString(indent + decl + "\n")
+	}
+}
+
+// writeAssigns copies the sql.Null variables into their fields, after the
+// Scan succeeded.
+func (s *rowScan) writeAssigns(sb *strings.Builder, indent string) {
+	for _, assign := range s.assigns {
+		sb.WriteString(indent + assign + "\n")
+	}
+}
+
+// needsNullable reports whether field's column may be NULL while its Go
+// type cannot hold NULL.
+func (g *RepositoryGenerator) needsNullable(field entity.Field) bool {
+	var tags []string
+	if field.IsPrimary {
+		tags = append(tags, "pk")
+	}
+	if field.IsNullable {
+		tags = append(tags, "null")
+	}
+	if field.IsNotNull {
+		tags = append(tags, "notnull")
+	}
+	mapping := g.mapper.MapFieldType(field.Type, field.UnderlyingType)
+	return g.mapper.Nullable(mapping, tags) && !g.mapper.CanHoldNull(field.Type)
+}
+
 // generateJoinResultStruct generates a struct for JOIN results
 func (g *RepositoryGenerator) generateJoinResultStruct(sb *strings.Builder, structName string, ent1, ent2 *entity.Entity) {
 	sb.WriteString(fmt.Sprintf("type %s struct {\n", structName))
@@ -566,14 +684,14 @@ func (g *RepositoryGenerator) getEntityColumns(ent *entity.Entity) []string {
 	fields := ent.GetGenerateableFields()
 	var columns []string
 	for _, field := range fields {
-		columns = append(columns, util.ToSnakeCase(field.Name))
+		columns = append(columns, field.ColumnName())
 	}
 	return columns
 }
 
 // generateGetByCompositePKMethod generates a GET method for entities with composite primary keys
 func (g *RepositoryGenerator) generateGetByCompositePKMethod(sb *strings.Builder, ent *entity.Entity, fields []entity.Field) {
-	tableName := ent.GetTableName()
+	tableName := ent.GetQualifiedTableName()
 	pkFields := ent.GetPrimaryKeyFields()
 
 	sb.WriteString(fmt.Sprintf("func Get%s(ctx context.Context, db *sql.DB", ent.Name))
@@ -592,20 +710,29 @@ func (g *RepositoryGenerator) generateGetByCompositePKMethod(sb *strings.Builder
 		if !field.ShouldGenerate() {
 			continue
 		}
-		colName := util.ToSnakeCase(field.Name)
+		colName := field.ColumnName()
 		columns = append(columns, colName)
 	}
 
 	// Build WHERE clause for composite PK
 	var whereClause []string
 	for i, pkField := range pkFields {
-		whereClause = append(whereClause, fmt.Sprintf("%s = $%d", util.ToSnakeCase(pkField.Name), i+1))
+		whereClause = append(whereClause, fmt.Sprintf("%s = $%d", pkField.ColumnName(), i+1))
 	}
 
 	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", strings.Join(columns, ", "), tableName, strings.Join(whereClause, " AND "))
 	sb.WriteString(fmt.Sprintf("    query := `%s`\n", query))
 
+	var scan rowScan
+	for _, field := range fields {
+		if !field.ShouldGenerate() {
+			continue
+		}
+		g.scanInto(&scan, "item."+field.Name, field)
+	}
+
 	sb.WriteString(fmt.Sprintf("    var item %s\n", ent.Name))
+	scan.writeDecls(sb, "    ")
 	sb.WriteString("    err := db.QueryRowContext(ctx, query, ")
 
 	// Add PK parameters (use snake_case for parameter names)
@@ -615,27 +742,20 @@ func (g *RepositoryGenerator) generateGetByCompositePKMethod(sb *strings.Builder
 	}
 	sb.WriteString(strings.Join(scanParams, ", "))
 	sb.WriteString(").Scan(")
-
-	var scanFields []string
-	for _, field := range fields {
-		if !field.ShouldGenerate() {
-			continue
-		}
-		scanFields = append(scanFields, fmt.Sprintf("&item.%s", field.Name))
-	}
-	sb.WriteString(strings.Join(scanFields, ", "))
+	sb.WriteString(scan.args())
 	sb.WriteString(")\n")
 
 	sb.WriteString("    if err != nil {\n")
 	sb.WriteString("        return nil, err\n")
 	sb.WriteString("    }\n")
+	scan.writeAssigns(sb, "    ")
 	sb.WriteString("    return &item, nil\n")
 	sb.WriteString("}\n\n")
 }
 
 // generateDeleteByCompositePKMethod generates a DELETE method for entities with composite primary keys
 func (g *RepositoryGenerator) generateDeleteByCompositePKMethod(sb *strings.Builder, ent *entity.Entity, fields []entity.Field) {
-	tableName := ent.GetTableName()
+	tableName := ent.GetQualifiedTableName()
 	pkFields := ent.GetPrimaryKeyFields()
 
 	sb.WriteString(fmt.Sprintf("func Delete%s(ctx context.Context, db *sql.DB", ent.Name))
@@ -651,7 +771,7 @@ func (g *RepositoryGenerator) generateDeleteByCompositePKMethod(sb *strings.Buil
 	// Build WHERE clause for composite PK
 	var whereClause []string
 	for i, pkField := range pkFields {
-		whereClause = append(whereClause, fmt.Sprintf("%s = $%d", util.ToSnakeCase(pkField.Name), i+1))
+		whereClause = append(whereClause, fmt.Sprintf("%s = $%d", pkField.ColumnName(), i+1))
 	}
 
 	query := fmt.Sprintf("DELETE FROM %s WHERE %s", tableName, strings.Join(whereClause, " AND "))
@@ -2,6 +2,12 @@ package code
 
 import (
 	"context"
+	"errors"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
 	"strings"
 	"testing"
 
@@ -881,3 +887,477 @@ func TestGenerateFromInterfaceSmartQuerySingleReturn(t *testing.T) {
 		t.Error("SmartQuery single return should return &item")
 	}
 }
+
+func TestGenerateFromInterfaceEmbeddedFields(t *testing.T) {
+	gen := NewRepositoryGenerator()
+
+	ent := &entity.Entity{
+		Name: "Customer",
+		Fields: []entity.Field{
+			{Name: "ID", Type: "int64", IsPrimary: true, Identity: "identity"},
+			{Name: "ShippingStreet", Type: "string", Column: "ship_street", Selector: "Shipping.Street"},
+		},
+	}
+
+	repo := &entity.RepositoryInterface{
+		Name:       "CustomerRepository",
+		EntityName: "Customer",
+		Methods: []entity.RepositoryMethod{
+			{Name: "Create", Kind: entity.MethodCreate, EntityName: "Customer", Params: []entity.MethodParam{{Name: "item", Type: "*Customer"}}, ReturnsSingle: true, ReturnsError: true},
+			{Name: "Update", Kind: entity.MethodUpdate, EntityName: "Customer", Params: []entity.MethodParam{{Name: "item", Type: "*Customer"}}, ReturnsError: true},
+			{Name: "List", Kind: entity.MethodList, EntityName: "Customer", ReturnsError: true},
+		},
+	}
+
+	result, err := gen.GenerateFromInterface(context.Background(), "repository", ent, repo)
+	if err != nil {
+		t.Fatalf("GenerateFromInterface() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"INSERT INTO \"customer\" (ship_street) VALUES ($1)",
+		"item.Shipping.Street",
+		"&result.Shipping.Street",
+		"UPDATE \"customer\" SET ship_street = $1 WHERE id = $2",
+		"&item.Shipping.Street",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("generated code missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestGenerateFromInterfaceColumnOverrides(t *testing.T) {
+	gen := NewRepositoryGenerator()
+
+	ent := &entity.Entity{
+		Name: "Account",
+		Fields: []entity.Field{
+			{Name: "UserID", Type: "int64", IsPrimary: true, Column: "userid"},
+			{Name: "EMailAddr", Type: "string", Column: "email"},
+		},
+	}
+
+	repo := &entity.RepositoryInterface{
+		Name:       "AccountRepository",
+		EntityName: "Account",
+		Methods: []entity.RepositoryMethod{
+			{Name: "GetByID", Kind: entity.MethodGetByID, EntityName: "Account", Params: []entity.MethodParam{{Name: "id", Type: "int64"}}, ReturnsSingle: true, ReturnsError: true},
+			{Name: "FindByEMailAddr", Kind: entity.MethodFindBy, EntityName: "Account", Params: []entity.MethodParam{{Name: "email", Type: "string"}}, FindByFields: []string{"EMailAddr"}, ReturnsSingle: true, ReturnsError: true},
+			{Name: "Delete", Kind: entity.MethodDelete, EntityName: "Account", Params: []entity.MethodParam{{Name: "id", Type: "int64"}}, ReturnsError: true},
+		},
+	}
+
+	result, err := gen.GenerateFromInterface(context.Background(), "repository", ent, repo)
+	if err != nil {
+		t.Fatalf("GenerateFromInterface() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`SELECT userid, email FROM "account" WHERE userid = $1`,
+		`SELECT userid, email FROM "account" WHERE email = $1`,
+		`DELETE FROM "account" WHERE userid = $1`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("generated code missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestRepositoryGeneratorColumnOverrides(t *testing.T) {
+	gen := NewRepositoryGenerator()
+
+	account := &entity.Entity{
+		Name:      "Account",
+		TableName: "accounts",
+		Fields: []entity.Field{
+			{Name: "UserID", Type: "int64", IsPrimary: true, Column: "userid"},
+		},
+	}
+	session := &entity.Entity{
+		Name:      "Session",
+		TableName: "sessions",
+		Fields: []entity.Field{
+			{Name: "SessionID", Type: "int64", IsPrimary: true, Column: "sid"},
+			{Name: "AccountID", Type: "int64", Column: "acct", FKReference: &entity.FKReference{Table: "accounts", Column: "userid"}},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), "models", []*entity.Entity{account, session})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"SELECT userid FROM accounts WHERE userid = $1",
+		"DELETE FROM accounts WHERE userid = $1",
+		"JOIN accounts ON sessions.acct = accounts.userid WHERE sessions.sid = $1",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("generated code missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestRepositoryGeneratorNullableScan(t *testing.T) {
+	gen := NewRepositoryGenerator()
+
+	ent := &entity.Entity{
+		Name: "Profile",
+		Fields: []entity.Field{
+			{Name: "ID", Type: "int64", IsPrimary: true},
+			{Name: "Bio", Type: "*string"},
+			{Name: "Website", Type: "sql.NullString"},
+			{Name: "Nickname", Type: "string", IsNullable: true},
+			{Name: "Settings", Type: "json.RawMessage", IsNotNull: true},
+		},
+	}
+
+	repo := &entity.RepositoryInterface{
+		Name:       "ProfileRepository",
+		EntityName: "Profile",
+		Methods: []entity.RepositoryMethod{
+			{Name: "GetByID", Kind: entity.MethodGetByID, EntityName: "Profile", Params: []entity.MethodParam{{Name: "id", Type: "int64"}}, ReturnsSingle: true, ReturnsError: true},
+		},
+	}
+
+	want := []string{
+		"var nullNickname sql.Null[string]",
+		"&item.ID, &item.Bio, &item.Website, &nullNickname, &item.Settings",
+		"item.Nickname = nullNickname.V",
+	}
+
+	result, err := gen.GenerateFromInterface(context.Background(), "repository", ent, repo)
+	if err != nil {
+		t.Fatalf("GenerateFromInterface() error = %v", err)
+	}
+	for _, w := range want {
+		if !strings.Contains(result, w) {
+			t.Errorf("GenerateFromInterface() missing %q:\n%s", w, result)
+		}
+	}
+
+	result, err = gen.Generate(context.Background(), "models", []*entity.Entity{ent})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, w := range want {
+		if !strings.Contains(result, w) {
+			t.Errorf("Generate() missing %q:\n%s", w, result)
+		}
+	}
+
+	ent.Fields[3].IsNullable = false
+	result, err = gen.Generate(context.Background(), "models", []*entity.Entity{ent})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if strings.Contains(result, "sql.Null[") {
+		t.Errorf("Generate() scans through sql.Null without nullable value fields:\n%s", result)
+	}
+}
+
+// Generated repositories of several entities must compile side by side in
+// one package.
+func TestRepositoryGeneratorRepositoriesShareAPackage(t *testing.T) {
+	gen := NewRepositoryGenerator()
+
+	author := &entity.Entity{
+		Name: "Author",
+		Fields: []entity.Field{
+			{Name: "ID", Type: "int64", IsPrimary: true},
+			{Name: "Bio", Type: "string", IsNullable: true},
+		},
+	}
+	post := &entity.Entity{
+		Name: "Post",
+		Fields: []entity.Field{
+			{Name: "ID", Type: "int64", IsPrimary: true},
+			{Name: "Subtitle", Type: "string", IsNullable: true},
+			{Name: "Views", Type: "int", IsNullable: true},
+		},
+	}
+	models := "package blog\n\ntype Author struct {\n\tID  int64\n\tBio string\n}\n\n" +
+		"type Post struct {\n\tID       int64\n\tSubtitle string\n\tViews    int\n}\n\n" +
+		"type AuthorRepository interface{}\n\ntype PostRepository interface{}\n"
+
+	repository := func(ent *entity.Entity) *entity.RepositoryInterface {
+		return &entity.RepositoryInterface{
+			Name:       ent.Name + "Repository",
+			EntityName: ent.Name,
+			Methods: []entity.RepositoryMethod{
+				{Name: "GetByID", Kind: entity.MethodGetByID, EntityName: ent.Name, Params: []entity.MethodParam{{Name: "id", Type: "int64"}}, ReturnsSingle: true, ReturnsError: true},
+				{Name: "List", Kind: entity.MethodList, EntityName: ent.Name, ReturnsError: true},
+			},
+		}
+	}
+
+	t.Run("interface repositories", func(t *testing.T) {
+		files := map[string]string{"models.go": models}
+		for _, ent := range []*entity.Entity{author, post} {
+			src, err := gen.GenerateFromInterface(context.Background(), "blog", ent, repository(ent))
+			if err != nil {
+				t.Fatalf("GenerateFromInterface() error = %v", err)
+			}
+			files[strings.ToLower(ent.Name)+"_repository.gen.go"] = src
+		}
+		typeCheck(t, files)
+	})
+
+	t.Run("generated repositories", func(t *testing.T) {
+		files := make(map[string]string)
+		for _, ent := range []*entity.Entity{author, post} {
+			src, err := gen.Generate(context.Background(), "blog", []*entity.Entity{ent})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			files[strings.ToLower(ent.Name)+".gen.go"] = src
+		}
+		typeCheck(t, files)
+	})
+}
+
+// typeCheck type-checks files as one package and reports every error.
+func typeCheck(t *testing.T, files map[string]string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	var parsed []*ast.File
+	for name, src := range files {
+		f, err := parser.ParseFile(fset, name, src, 0)
+		if err != nil {
+			t.Fatalf("parse %s: %v\n%s", name, err, src)
+		}
+		parsed = append(parsed, f)
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			t.Errorf("type-check: %v", err)
+		},
+	}
+	conf.Check("blog", fset, parsed, nil)
+}
+
+func TestRepositoryGeneratorIdentityInsert(t *testing.T) {
+	gen := NewRepositoryGenerator()
+
+	tests := []struct {
+		name          string
+		identity      string
+		wantLegacy    string
+		wantInterface string
+		wantUpdate    string
+	}{
+		{
+			name:          "explicit primary key",
+			wantLegacy:    "INSERT INTO ticket (id, title) VALUES ($1, $2) RETURNING id",
+			wantInterface: `INSERT INTO "ticket" (id, title) VALUES ($1, $2) RETURNING id, title`,
+			wantUpdate:    `UPDATE "ticket" SET title = $1 WHERE id = $2`,
+		},
+		{
+			name:          "identity primary key",
+			identity:      "identity",
+			wantLegacy:    "INSERT INTO ticket (title) VALUES ($1) RETURNING id",
+			wantInterface: `INSERT INTO "ticket" (title) VALUES ($1) RETURNING id, title`,
+			wantUpdate:    `UPDATE "ticket" SET title = $1 WHERE id = $2`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ent := &entity.Entity{
+				Name: "Ticket",
+				Fields: []entity.Field{
+					{Name: "ID", Type: "int64", IsPrimary: true, Identity: tt.identity},
+					{Name: "Title", Type: "string"},
+				},
+			}
+			repo := &entity.RepositoryInterface{
+				Name:       "TicketRepository",
+				EntityName: "Ticket",
+				Methods: []entity.RepositoryMethod{
+					{Name: "Create", Kind: entity.MethodCreate, EntityName: "Ticket", Params: []entity.MethodParam{{Name: "item", Type: "*Ticket"}}, ReturnsSingle: true, ReturnsError: true},
+					{Name: "Update", Kind: entity.MethodUpdate, EntityName: "Ticket", Params: []entity.MethodParam{{Name: "item", Type: "*Ticket"}}, ReturnsError: true},
+				},
+			}
+
+			legacy, err := gen.Generate(context.Background(), "models", []*entity.Entity{ent})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if !strings.Contains(legacy, tt.wantLegacy) {
+				t.Errorf("Generate() missing %q:\n%s", tt.wantLegacy, legacy)
+			}
+
+			result, err := gen.GenerateFromInterface(context.Background(), "repository", ent, repo)
+			if err != nil {
+				t.Fatalf("GenerateFromInterface() error = %v", err)
+			}
+			for _, want := range []string{tt.wantInterface, tt.wantUpdate} {
+				if !strings.Contains(result, want) {
+					t.Errorf("GenerateFromInterface() missing %q:\n%s", want, result)
+				}
+			}
+		})
+	}
+}
+
+func TestRepositoryGeneratorGeneratedColumns(t *testing.T) {
+	gen := NewRepositoryGenerator()
+
+	ent := &entity.Entity{
+		Name: "Post",
+		Fields: []entity.Field{
+			{Name: "ID", Type: "int64", IsPrimary: true},
+			{Name: "Title", Type: "string"},
+			{Name: "Slug", Type: "string", GeneratedExpr: "lower(title)"},
+		},
+	}
+	repo := &entity.RepositoryInterface{
+		Name:       "PostRepository",
+		EntityName: "Post",
+		Methods: []entity.RepositoryMethod{
+			{Name: "Create", Kind: entity.MethodCreate, EntityName: "Post", Params: []entity.MethodParam{{Name: "item", Type: "*Post"}}, ReturnsSingle: true, ReturnsError: true},
+			{Name: "Update", Kind: entity.MethodUpdate, EntityName: "Post", Params: []entity.MethodParam{{Name: "item", Type: "*Post"}}, ReturnsError: true},
+			{Name: "List", Kind: entity.MethodList, EntityName: "Post", ReturnsError: true},
+		},
+	}
+
+	result, err := gen.GenerateFromInterface(context.Background(), "repository", ent, repo)
+	if err != nil {
+		t.Fatalf("GenerateFromInterface() error = %v", err)
+	}
+	for _, want := range []string{
+		`INSERT INTO "post" (id, title) VALUES ($1, $2) RETURNING id, title, slug`,
+		".Scan(&result.ID, &result.Title, &result.Slug)",
+		`UPDATE "post" SET title = $1 WHERE id = $2`,
+		`SELECT id, title, slug FROM "post"`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("GenerateFromInterface() missing %q:\n%s", want, result)
+		}
+	}
+
+	legacy, err := gen.Generate(context.Background(), "models", []*entity.Entity{ent})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, want := range []string{
+		"INSERT INTO post (id, title) VALUES ($1, $2) RETURNING id",
+		"UPDATE post SET title = $1 WHERE id = $2",
+		"SELECT id, title, slug FROM post WHERE id = $1",
+	} {
+		if !strings.Contains(legacy, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, legacy)
+		}
+	}
+}
+
+func TestRepositoryGeneratorPartitionedTable(t *testing.T) {
+	gen := NewRepositoryGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name:        "Event",
+			PartitionBy: &entity.Partitioning{Method: "RANGE", Columns: []string{"created_at"}},
+			Partitions:  []entity.Partition{{Name: "event_2026_01", Bound: "FOR VALUES FROM ('2026-01-01') TO ('2026-02-01')"}},
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "CreatedAt", Type: "time.Time", IsPrimary: true},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), "models", entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if !strings.Contains(result, "FROM event") {
+		t.Errorf("Generate() List does not query the parent table:\n%s", result)
+	}
+	if strings.Contains(result, "event_2026_01") {
+		t.Errorf("Generate() queries a child partition:\n%s", result)
+	}
+}
+
+func TestRepositoryGeneratorViews(t *testing.T) {
+	gen := NewRepositoryGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name:         "DailySales",
+			View:         true,
+			Materialized: true,
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Total", Type: "int64"},
+			},
+		},
+		{
+			Name: "ActiveUser",
+			View: true,
+			Fields: []entity.Field{
+				{Name: "Name", Type: "string"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), "models", entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"func GetDailySalesByID(",
+		"func ListDailySales(",
+		"func RefreshDailySales(ctx context.Context, db *sql.DB) error {",
+		"`REFRESH MATERIALIZED VIEW daily_sales`",
+		"func ListActiveUser(",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, result)
+		}
+	}
+	for _, unwanted := range []string{"func Create", "func Update", "func Delete", "func GetActiveUserByID", "func RefreshActiveUser"} {
+		if strings.Contains(result, unwanted) {
+			t.Errorf("Generate() contains %q for a view:\n%s", unwanted, result)
+		}
+	}
+}
+
+func TestGenerateFromInterfaceViews(t *testing.T) {
+	gen := NewRepositoryGenerator()
+
+	view := &entity.Entity{
+		Name:         "DailySales",
+		View:         true,
+		Materialized: true,
+		Fields: []entity.Field{
+			{Name: "ID", Type: "int64", IsPrimary: true},
+		},
+	}
+	refresh := entity.RepositoryMethod{Name: "Refresh", Kind: entity.MethodRefresh, ReturnsError: true}
+	create := entity.RepositoryMethod{Name: "Create", Kind: entity.MethodCreate, Params: []entity.MethodParam{{Name: "item", Type: "*DailySales"}}, ReturnsSingle: true, ReturnsError: true}
+
+	repo := &entity.RepositoryInterface{Name: "DailySalesRepository", Methods: []entity.RepositoryMethod{refresh}}
+	result, err := gen.GenerateFromInterface(context.Background(), "repository", view, repo)
+	if err != nil {
+		t.Fatalf("GenerateFromInterface() error = %v", err)
+	}
+	if !strings.Contains(result, "func (r *DailySalesRepositoryImpl) Refresh(ctx context.Context) error {") ||
+		!strings.Contains(result, "`REFRESH MATERIALIZED VIEW \"daily_sales\"`") {
+		t.Errorf("GenerateFromInterface() missing Refresh:\n%s", result)
+	}
+
+	repo.Methods = []entity.RepositoryMethod{create}
+	if _, err := gen.GenerateFromInterface(context.Background(), "repository", view, repo); !errors.Is(err, ErrReadOnlyView) {
+		t.Errorf("GenerateFromInterface() error = %v, want %v", err, ErrReadOnlyView)
+	}
+
+	view.Materialized = false
+	repo.Methods = []entity.RepositoryMethod{refresh}
+	if _, err := gen.GenerateFromInterface(context.Background(), "repository", view, repo); !errors.Is(err, ErrNotMaterialized) {
+		t.Errorf("GenerateFromInterface() error = %v, want %v", err, ErrNotMaterialized)
+	}
+}
@@ -3,6 +3,7 @@ package generator
 import (
 	"context"
 
+	"github.com/n0xum/structify/internal/application/command"
 	"github.com/n0xum/structify/internal/domain/entity"
 	"github.com/n0xum/structify/internal/generator/code"
 	"github.com/n0xum/structify/internal/generator/sql"
@@ -20,8 +21,25 @@ func NewCompositeGenerator() *CompositeGenerator {
 	}
 }
 
-func (g *CompositeGenerator) GenerateSchema(ctx context.Context, entities []*entity.Entity) (string, error) {
-	return g.sqlGenerator.Generate(ctx, entities)
+func (g *CompositeGenerator) GenerateSchema(ctx context.Context, entities []*entity.Entity, opts command.SchemaOptions) (string, error) {
+	return g.sqlGenerator.GenerateWithOptions(ctx, entities, schemaOptions(opts))
+}
+
+// schemaOptions maps the options of a schema command onto those of the SQL
+// generator.
+func schemaOptions(opts command.SchemaOptions) sql.SchemaOptions {
+	sqlOpts := sql.SchemaOptions{IfNotExists: opts.IfNotExists}
+	switch opts.Mode {
+	case command.ModeDrop:
+		sqlOpts.Mode = sql.ModeDrop
+	case command.ModeReset:
+		sqlOpts.Mode = sql.ModeReset
+	}
+	return sqlOpts
+}
+
+func (g *CompositeGenerator) GenerateMigration(ctx context.Context, from, to []*entity.Entity) (*entity.Migration, error) {
+	return g.sqlGenerator.Diff(ctx, from, to)
 }
 
 func (g *CompositeGenerator) GenerateCode(ctx context.Context, packageName string, entities []*entity.Entity) (string, error) {
@@ -4,7 +4,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/n0xum/structify/internal/application/command"
 	"github.com/n0xum/structify/internal/domain/entity"
+	"github.com/n0xum/structify/internal/generator/sql"
 )
 
 func TestNewCompositeGenerator(t *testing.T) {
@@ -36,7 +38,7 @@ func TestCompositeGeneratorGenerateSchema(t *testing.T) {
 		},
 	}
 
-	result, err := gen.GenerateSchema(ctx, entities)
+	result, err := gen.GenerateSchema(ctx, entities, command.SchemaOptions{})
 	if err != nil {
 		t.Fatalf("GenerateSchema() error = %v", err)
 	}
@@ -54,6 +56,27 @@ func TestCompositeGeneratorGenerateSchema(t *testing.T) {
 	}
 }
 
+func TestSchemaOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts command.SchemaOptions
+		want sql.SchemaOptions
+	}{
+		{name: "default", want: sql.SchemaOptions{}},
+		{name: "if not exists", opts: command.SchemaOptions{IfNotExists: true}, want: sql.SchemaOptions{IfNotExists: true}},
+		{name: "drop", opts: command.SchemaOptions{Mode: command.ModeDrop}, want: sql.SchemaOptions{Mode: sql.ModeDrop}},
+		{name: "reset", opts: command.SchemaOptions{Mode: command.ModeReset, IfNotExists: true}, want: sql.SchemaOptions{Mode: sql.ModeReset, IfNotExists: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schemaOptions(tt.opts); got != tt.want {
+				t.Errorf("schemaOptions(%+v) = %+v, want %+v", tt.opts, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestCompositeGeneratorGenerateCode(t *testing.T) {
 	gen := NewCompositeGenerator()
 
@@ -87,6 +110,31 @@ func TestCompositeGeneratorGenerateCode(t *testing.T) {
 	}
 }
 
+func TestCompositeGeneratorGenerateMigration(t *testing.T) {
+	gen := NewCompositeGenerator()
+
+	from := []*entity.Entity{
+		{Name: "User", Fields: []entity.Field{{Name: "ID", Type: "int64", IsPrimary: true}}},
+	}
+	to := []*entity.Entity{
+		{Name: "User", Fields: []entity.Field{
+			{Name: "ID", Type: "int64", IsPrimary: true},
+			{Name: "Email", Type: "*string"},
+		}},
+	}
+
+	migration, err := gen.GenerateMigration(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("GenerateMigration() error = %v", err)
+	}
+	if !contains(migration.UpSQL(), `ALTER TABLE "user" ADD COLUMN "email" VARCHAR(255);`) {
+		t.Errorf("UpSQL() missing ADD COLUMN:\n%s", migration.UpSQL())
+	}
+	if !contains(migration.DownSQL(), `ALTER TABLE "user" DROP COLUMN "email";`) {
+		t.Errorf("DownSQL() missing DROP COLUMN:\n%s", migration.DownSQL())
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) > 0 && len(substr) > 0 && findSubstring(s, substr)
 }
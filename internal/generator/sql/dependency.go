@@ -0,0 +1,152 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/n0xum/structify/internal/domain/entity"
+)
+
+// sortTables returns the tables among entities ordered so that each table
+// comes after the tables its foreign keys reference, keeping the input order
+// where foreign keys allow it. A reference closing a cycle, including a
+// self-reference, cannot be created inline; deferred holds those referenced
+// table names per table. Views are left out.
+func (g *SchemaGenerator) sortTables(entities []*entity.Entity) (tables []*entity.Entity, deferred map[*entity.Entity]map[string]bool) {
+	var candidates []*entity.Entity
+	for _, ent := range entities {
+		if !ent.View {
+			candidates = append(candidates, ent)
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*entity.Entity]int, len(candidates))
+	deferred = make(map[*entity.Entity]map[string]bool)
+
+	var visit func(ent *entity.Entity)
+	visit = func(ent *entity.Entity) {
+		state[ent] = visiting
+		for _, field := range ent.GetGenerateableFields() {
+			if field.FKReference == nil {
+				continue
+			}
+			target := findTable(candidates, field.FKReference.Table)
+			if target == nil {
+				continue
+			}
+			switch state[target] {
+			case visiting:
+				if deferred[ent] == nil {
+					deferred[ent] = make(map[string]bool)
+				}
+				deferred[ent][field.FKReference.Table] = true
+			case unvisited:
+				visit(target)
+			}
+		}
+		state[ent] = visited
+		tables = append(tables, ent)
+	}
+
+	for _, ent := range candidates {
+		if state[ent] == unvisited {
+			visit(ent)
+		}
+	}
+	return tables, deferred
+}
+
+// generateDrop drops the views and tables of entities in the reverse of the
+// order they are created in, so that dependents go first. CASCADE also
+// removes objects outside the model that depend on them.
+func (g *SchemaGenerator) generateDrop(entities []*entity.Entity) string {
+	var sb strings.Builder
+
+	for i := len(entities) - 1; i >= 0; i-- {
+		if ent := entities[i]; ent.View {
+			sb.WriteString(fmt.Sprintf("DROP %s IF EXISTS %s CASCADE;\n", g.objectKind(ent), g.getTableName(ent)))
+		}
+	}
+	tables, _ := g.sortTables(entities)
+	for i := len(tables) - 1; i >= 0; i-- {
+		sb.WriteString(fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE;\n", g.getTableName(tables[i])))
+	}
+
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// findTable returns the entity whose table is name, bare or qualified with
+// a schema, or nil.
+func findTable(entities []*entity.Entity, name string) *entity.Entity {
+	for _, ent := range entities {
+		if ent.HasTable(name) {
+			return ent
+		}
+	}
+	return nil
+}
+
+// generateDeferredForeignKeys adds the foreign keys left out of the CREATE
+// TABLE statements with ALTER TABLE, once all tables exist. ADD CONSTRAINT
+// has no IF NOT EXISTS; re-runnable DDL drops a named key first.
+func (g *SchemaGenerator) generateDeferredForeignKeys(tables []*entity.Entity, deferred map[*entity.Entity]map[string]bool, opts SchemaOptions) string {
+	var sb strings.Builder
+
+	for _, ent := range tables {
+		refs := deferred[ent]
+		if len(refs) == 0 {
+			continue
+		}
+		tableName := g.getTableName(ent)
+		fields := ent.GetGenerateableFields()
+		names := g.newConstraintNamer(ent)
+
+		for _, field := range fields {
+			if field.FKReference == nil || field.FKGroup != "" || !refs[field.FKReference.Table] {
+				continue
+			}
+			column := field.ColumnName()
+			reference := g.foreignKeyReference(
+				[]string{pq.QuoteIdentifier(column)},
+				field.FKReference.Table,
+				[]string{pq.QuoteIdentifier(field.FKReference.Column)},
+				field.FKOnDelete, field.FKOnUpdate)
+			sb.WriteString(g.addForeignKey(tableName, names.foreignKey(column), reference, opts))
+		}
+
+		fkGroups := g.groupFieldsByFK(fields)
+		for _, groupName := range groupNames(fields, fkGroups, fkGroupKey) {
+			if len(fkGroups[groupName]) < 2 {
+				continue
+			}
+			refTable, reference, ok := g.compositeForeignKey(fkGroups[groupName])
+			if !ok || !refs[refTable] {
+				continue
+			}
+			sb.WriteString(g.addForeignKey(tableName, groupName, reference, opts))
+		}
+	}
+
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// addForeignKey renders the ALTER TABLE statement adding one foreign key.
+func (g *SchemaGenerator) addForeignKey(tableName, name, reference string, opts SchemaOptions) string {
+	var drop string
+	if opts.IfNotExists && name != "" {
+		drop = fmt.Sprintf(" DROP CONSTRAINT IF EXISTS %s,", pq.QuoteIdentifier(name))
+	}
+	return fmt.Sprintf("ALTER TABLE %s%s ADD %sFOREIGN KEY %s;\n", tableName, drop, constraintPrefix(name), reference)
+}
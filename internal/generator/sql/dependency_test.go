@@ -0,0 +1,171 @@
+package sql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/n0xum/structify/internal/domain/entity"
+)
+
+func TestSchemaGeneratorSortTables(t *testing.T) {
+	ref := func(table string) *entity.FKReference {
+		return &entity.FKReference{Table: table, Column: "id"}
+	}
+	comment := &entity.Entity{Name: "Comment", Fields: []entity.Field{
+		{Name: "ID", Type: "int64", IsPrimary: true},
+		{Name: "PostID", Type: "int64", FKReference: ref("post")},
+	}}
+	post := &entity.Entity{Name: "Post", Fields: []entity.Field{
+		{Name: "ID", Type: "int64", IsPrimary: true},
+		{Name: "AuthorID", Type: "int64", FKReference: ref("author")},
+		{Name: "ExternalID", Type: "int64", FKReference: ref("legacy_posts")},
+	}}
+	author := &entity.Entity{Name: "Author", Fields: []entity.Field{
+		{Name: "ID", Type: "int64", IsPrimary: true},
+	}}
+	tag := &entity.Entity{Name: "Tag", Fields: []entity.Field{
+		{Name: "ID", Type: "int64", IsPrimary: true},
+	}}
+	report := &entity.Entity{Name: "Report", View: true, ViewSQL: "SELECT 1", Fields: []entity.Field{
+		{Name: "ID", Type: "int64"},
+	}}
+
+	gen := NewSchemaGenerator()
+	tables, deferred := gen.sortTables([]*entity.Entity{comment, tag, report, post, author})
+
+	var got []string
+	for _, ent := range tables {
+		got = append(got, ent.Name)
+	}
+	want := []string{"Author", "Post", "Comment", "Tag"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("sortTables() order = %v, want %v", got, want)
+	}
+	if len(deferred) != 0 {
+		t.Errorf("sortTables() deferred = %v, want none", deferred)
+	}
+}
+
+func TestSchemaGeneratorGenerateWithForeignKeyCycles(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Team",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "CaptainID", Type: "*int64", FKReference: &entity.FKReference{Table: "player", Column: "id"}, FKOnDelete: "SET_NULL"},
+			},
+		},
+		{
+			Name: "Player",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "TeamID", Type: "int64", FKReference: &entity.FKReference{Table: "team", Column: "id"}},
+				{Name: "MentorID", Type: "*int64", FKReference: &entity.FKReference{Table: "player", Column: "id"}},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `CREATE TABLE "player" (
+    "id" BIGINT CONSTRAINT "pk_player" PRIMARY KEY,
+    "team_id" BIGINT NOT NULL,
+    "mentor_id" BIGINT
+);
+
+CREATE TABLE "team" (
+    "id" BIGINT CONSTRAINT "pk_team" PRIMARY KEY,
+    "captain_id" BIGINT CONSTRAINT "fk_team_captain_id" REFERENCES "player"("id") ON DELETE SET NULL
+);
+
+ALTER TABLE "player" ADD CONSTRAINT "fk_player_team_id" FOREIGN KEY ("team_id") REFERENCES "team" ("id");
+ALTER TABLE "player" ADD CONSTRAINT "fk_player_mentor_id" FOREIGN KEY ("mentor_id") REFERENCES "player" ("id");
+
+`
+	if result != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", result, want)
+	}
+}
+
+func TestSchemaGeneratorGenerateWithCompositeForeignKeyCycle(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Node",
+			Fields: []entity.Field{
+				{Name: "TreeID", Type: "int64", IsPrimary: true},
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "ParentTreeID", Type: "*int64", FKGroup: "fk_parent", FKReference: &entity.FKReference{Table: "node", Column: "tree_id"}},
+				{Name: "ParentID", Type: "*int64", FKGroup: "fk_parent", FKReference: &entity.FKReference{Table: "node", Column: "id"}, FKOnDelete: "CASCADE"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if strings.Contains(result, "    FOREIGN KEY") {
+		t.Errorf("Generate() kept the self-referencing composite key inline:\n%s", result)
+	}
+	want := `ALTER TABLE "node" ADD CONSTRAINT "fk_parent" FOREIGN KEY ("parent_tree_id", "parent_id") REFERENCES "node" ("tree_id", "id") ON DELETE CASCADE;`
+	if !strings.Contains(result, want) {
+		t.Errorf("Generate() missing %q:\n%s", want, result)
+	}
+}
+
+func TestSchemaGeneratorGenerateDrop(t *testing.T) {
+	entities := []*entity.Entity{
+		{
+			Name: "Comment",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "PostID", Type: "int64", FKReference: &entity.FKReference{Table: "blog.post", Column: "id"}},
+			},
+		},
+		{Name: "Post", Schema: "blog", Fields: []entity.Field{{Name: "ID", Type: "int64", IsPrimary: true}}},
+		{Name: "PostStats", View: true, Materialized: true, ViewSQL: "SELECT count(*) FROM blog.post"},
+		{Name: "RecentPost", View: true, ViewSQL: "SELECT * FROM blog.post"},
+	}
+
+	drop := `DROP VIEW IF EXISTS "recent_post" CASCADE;
+DROP MATERIALIZED VIEW IF EXISTS "post_stats" CASCADE;
+DROP TABLE IF EXISTS "comment" CASCADE;
+DROP TABLE IF EXISTS "blog"."post" CASCADE;
+
+`
+
+	tests := []struct {
+		name       string
+		mode       SchemaMode
+		wantPrefix string
+		wantCreate bool
+	}{
+		{name: "drop", mode: ModeDrop, wantPrefix: drop},
+		{name: "reset", mode: ModeReset, wantPrefix: drop + "CREATE SCHEMA IF NOT EXISTS \"blog\";\n", wantCreate: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewSchemaGenerator()
+			result, err := gen.GenerateWithOptions(context.Background(), entities, SchemaOptions{Mode: tt.mode})
+			if err != nil {
+				t.Fatalf("GenerateWithOptions() error = %v", err)
+			}
+			if !strings.HasPrefix(result, tt.wantPrefix) {
+				t.Errorf("GenerateWithOptions() =\n%s\nwant prefix\n%s", result, tt.wantPrefix)
+			}
+			if got := strings.Contains(result, "CREATE TABLE"); got != tt.wantCreate {
+				t.Errorf("GenerateWithOptions() creates tables = %v, want %v", got, tt.wantCreate)
+			}
+		})
+	}
+}
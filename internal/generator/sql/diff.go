@@ -0,0 +1,634 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/n0xum/structify/internal/domain/entity"
+)
+
+// ErrUnnamedConstraint is returned by Diff when the naming strategy leaves a
+// constraint unnamed, so that a migration could not drop it again.
+var ErrUnnamedConstraint = errors.New("constraint has no name to refer to in a migration")
+
+// Diff compares two versions of the model and returns the migration between
+// them. Tables, views and columns are matched by name, so a rename shows up
+// as a drop and an add. Constraints are matched by the names the naming
+// strategy gives them; it must name all of them.
+func (g *SchemaGenerator) Diff(ctx context.Context, from, to []*entity.Entity) (*entity.Migration, error) {
+	up, err := g.diffSteps(from, to)
+	if err != nil {
+		return nil, err
+	}
+	down, err := g.diffSteps(to, from)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Migration{Up: up, Down: down}, nil
+}
+
+// tableConstraint is a named constraint in the form ALTER TABLE ... ADD
+// CONSTRAINT takes.
+type tableConstraint struct {
+	name       string
+	definition string
+	foreignKey bool
+}
+
+// tablePair is a table present in both versions of the model.
+type tablePair struct {
+	from, to *entity.Entity
+}
+
+// diffSteps returns the steps taking the schema of from to the schema of to.
+// Dependent objects are dropped before what they depend on and created
+// after it: views, foreign keys and indexes are dropped first; new tables,
+// table options, partitions, columns, constraints, indexes and comments
+// follow; removed columns and tables go last but one, and views are
+// recreated at the end.
+func (g *SchemaGenerator) diffSteps(from, to []*entity.Entity) ([]entity.MigrationStep, error) {
+	var steps []entity.MigrationStep
+	add := func(sql string) {
+		if sql = strings.TrimSpace(sql); sql != "" {
+			steps = append(steps, entity.MigrationStep{SQL: sql})
+		}
+	}
+	warn := func(sql, warning string) {
+		steps = append(steps, entity.MigrationStep{SQL: sql, Warning: warning})
+	}
+	destroy := func(sql, warning string) {
+		steps = append(steps, entity.MigrationStep{SQL: sql, Destructive: true, Warning: warning})
+	}
+	manual := func(warning string) {
+		steps = append(steps, entity.MigrationStep{Warning: warning})
+	}
+
+	fromTables, _ := g.sortTables(from)
+	toTables, _ := g.sortTables(to)
+
+	var pairs []tablePair
+	var added []*entity.Entity
+	for _, ent := range toTables {
+		if old := findQualified(fromTables, ent); old != nil {
+			pairs = append(pairs, tablePair{from: old, to: ent})
+		} else {
+			added = append(added, ent)
+		}
+	}
+
+	oldConstraints := make(map[*entity.Entity][]tableConstraint)
+	newConstraints := make(map[*entity.Entity][]tableConstraint)
+	for _, pair := range pairs {
+		var err error
+		if oldConstraints[pair.to], err = g.tableConstraints(pair.from); err != nil {
+			return nil, err
+		}
+		if newConstraints[pair.to], err = g.tableConstraints(pair.to); err != nil {
+			return nil, err
+		}
+	}
+
+	// Extensions and schemas the new version needs
+	for _, ext := range missing(g.requiredExtensions(from), g.requiredExtensions(to)) {
+		add(fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s;", pq.QuoteIdentifier(ext)))
+	}
+	for _, schema := range missing(g.schemas(from), g.schemas(to)) {
+		add(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;", pq.QuoteIdentifier(schema)))
+	}
+
+	// Views that go away or change
+	stale := g.staleViews(from, to, pairs)
+	for i := len(from) - 1; i >= 0; i-- {
+		old := from[i]
+		if !old.View {
+			continue
+		}
+		if findQualified(to, old) == nil || stale[old.GetQualifiedTableName()] {
+			add(fmt.Sprintf("DROP %s %s;", g.objectKind(old), g.getTableName(old)))
+		}
+	}
+
+	// Constraints that go away or change, foreign keys first
+	for _, foreignKeys := range []bool{true, false} {
+		for _, pair := range pairs {
+			for _, c := range changedConstraints(oldConstraints[pair.to], newConstraints[pair.to]) {
+				if c.foreignKey == foreignKeys {
+					add(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", g.getTableName(pair.to), pq.QuoteIdentifier(c.name)))
+				}
+			}
+		}
+	}
+
+	// Indexes that go away or change
+	for _, pair := range pairs {
+		oldIndexes, newIndexes := g.tableIndexes(pair.from), g.tableIndexes(pair.to)
+		for _, name := range g.indexNames(pair.from) {
+			if newIndexes[name] != oldIndexes[name] {
+				add(fmt.Sprintf("DROP INDEX %s;", entity.QuoteQualifiedName(entity.QualifiedName(pair.from.Schema, name))))
+			}
+		}
+	}
+
+	// New tables, in dependency order
+	created, deferred := g.sortTables(added)
+	for _, ent := range created {
+		tableName := g.getTableName(ent)
+		add(g.generateTable(ent, tableName, deferred[ent], SchemaOptions{}))
+		add(g.generatePartitions(ent, tableName, SchemaOptions{}))
+		add(g.generateIndexes(ent, tableName, SchemaOptions{}))
+		add(g.generateComments(ent, tableName))
+	}
+	add(g.generateDeferredForeignKeys(created, deferred, SchemaOptions{}))
+
+	// Changed table options and partitions
+	for _, pair := range pairs {
+		g.diffTableOptions(pair.from, pair.to, add, destroy, manual)
+	}
+
+	// New and changed columns
+	for _, pair := range pairs {
+		tableName := g.getTableName(pair.to)
+		for _, field := range pair.to.GetGenerateableFields() {
+			old, ok := findColumn(pair.from, field.ColumnName())
+			if !ok {
+				sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", tableName, g.columnDefinition(field))
+				if !g.nullable(field) && field.DefaultVal == "" && field.GeneratedExpr == "" && field.Identity == "" {
+					warn(sql, fmt.Sprintf("adding NOT NULL column %s.%s without a default fails if the table has rows",
+						pair.to.GetQualifiedTableName(), field.ColumnName()))
+				} else {
+					add(sql)
+				}
+				continue
+			}
+			g.diffColumn(tableName, pair.to.GetQualifiedTableName(), old, field, add, warn, destroy, manual)
+		}
+	}
+
+	// New and changed constraints, foreign keys last
+	for _, foreignKeys := range []bool{false, true} {
+		for _, pair := range pairs {
+			for _, c := range changedConstraints(newConstraints[pair.to], oldConstraints[pair.to]) {
+				if c.foreignKey == foreignKeys {
+					add(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;", g.getTableName(pair.to), pq.QuoteIdentifier(c.name), c.definition))
+				}
+			}
+		}
+	}
+
+	// New and changed indexes
+	for _, pair := range pairs {
+		oldIndexes, newIndexes := g.tableIndexes(pair.from), g.tableIndexes(pair.to)
+		for _, name := range g.indexNames(pair.to) {
+			if newIndexes[name] != oldIndexes[name] {
+				add(newIndexes[name])
+			}
+		}
+	}
+
+	// Changed comments
+	for _, pair := range pairs {
+		add(g.diffComments(pair.from, pair.to))
+	}
+
+	// Columns that go away
+	for _, pair := range pairs {
+		for _, field := range pair.from.GetGenerateableFields() {
+			if _, ok := findColumn(pair.to, field.ColumnName()); !ok {
+				destroy(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", g.getTableName(pair.to), pq.QuoteIdentifier(field.ColumnName())),
+					fmt.Sprintf("drops column %s.%s and its data", pair.to.GetQualifiedTableName(), field.ColumnName()))
+			}
+		}
+	}
+
+	// Tables that go away, dependents first
+	for i := len(fromTables) - 1; i >= 0; i-- {
+		old := fromTables[i]
+		if findQualified(toTables, old) == nil {
+			destroy(fmt.Sprintf("DROP TABLE %s;", g.getTableName(old)),
+				fmt.Sprintf("drops table %s and its data", old.GetQualifiedTableName()))
+		}
+	}
+
+	// New and changed views
+	for _, view := range to {
+		if !view.View {
+			continue
+		}
+		if old := findQualified(from, view); old != nil && !stale[view.GetQualifiedTableName()] {
+			add(g.diffComments(old, view))
+			continue
+		}
+		viewName := g.getTableName(view)
+		add(g.generateView(view, viewName, SchemaOptions{}))
+		if view.Materialized {
+			add(g.generateIndexes(view, viewName, SchemaOptions{}))
+		}
+		add(g.generateComments(view, viewName))
+	}
+
+	return steps, nil
+}
+
+// diffColumn adds the steps changing the type, nullability, default and
+// generation expression of a column from old to field.
+func (g *SchemaGenerator) diffColumn(tableName, qualifiedName string, old, field entity.Field, add func(string), warn, destroy func(string, string), manual func(string)) {
+	column := pq.QuoteIdentifier(field.ColumnName())
+
+	if oldType, newType := g.columnType(old), g.columnType(field); oldType != newType {
+		destroy(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;", tableName, column, newType, column, newType),
+			fmt.Sprintf("changing %s.%s from %s to %s can fail or lose data", qualifiedName, field.ColumnName(), oldType, newType))
+	}
+
+	switch oldNullable, newNullable := g.nullable(old), g.nullable(field); {
+	case oldNullable && !newNullable:
+		warn(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL;", tableName, column),
+			fmt.Sprintf("%s.%s becomes NOT NULL, which fails if it holds NULLs", qualifiedName, field.ColumnName()))
+	case !oldNullable && newNullable:
+		add(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL;", tableName, column))
+	}
+
+	switch {
+	case old.DefaultVal == field.DefaultVal:
+	case field.DefaultVal == "":
+		add(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;", tableName, column))
+	default:
+		add(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s;", tableName, column, field.DefaultVal))
+	}
+
+	switch {
+	case old.GeneratedExpr == field.GeneratedExpr:
+	case field.GeneratedExpr == "":
+		add(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP EXPRESSION;", tableName, column))
+	case old.GeneratedExpr == "":
+		manual(fmt.Sprintf("%s.%s becomes a generated column, which PostgreSQL cannot alter; drop the column and add it again",
+			qualifiedName, field.ColumnName()))
+	default:
+		warn(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET EXPRESSION AS (%s);", tableName, column, field.GeneratedExpr),
+			fmt.Sprintf("%s.%s gets a new generation expression, which needs PostgreSQL 17 and rewrites the table", qualifiedName, field.ColumnName()))
+	}
+}
+
+// diffTableOptions adds the steps changing the persistence, storage
+// parameters and partitions of a table from old to ent. PostgreSQL cannot
+// change the partition key of a table, so that only gets a warning.
+func (g *SchemaGenerator) diffTableOptions(old, ent *entity.Entity, add func(string), destroy func(string, string), manual func(string)) {
+	tableName := g.getTableName(ent)
+
+	if old.Unlogged != ent.Unlogged {
+		persistence := "LOGGED"
+		if ent.Unlogged {
+			persistence = "UNLOGGED"
+		}
+		add(fmt.Sprintf("ALTER TABLE %s SET %s;", tableName, persistence))
+	}
+
+	oldParams, newParams := storageParams(old), storageParams(ent)
+	var set, reset []string
+	for _, param := range ent.StorageParams {
+		if oldParams[storageParamName(param)] != param {
+			set = append(set, param)
+		}
+	}
+	for _, param := range old.StorageParams {
+		if name := storageParamName(param); newParams[name] == "" {
+			reset = append(reset, name)
+		}
+	}
+	if len(set) > 0 {
+		add(fmt.Sprintf("ALTER TABLE %s SET (%s);", tableName, strings.Join(set, ", ")))
+	}
+	if len(reset) > 0 {
+		add(fmt.Sprintf("ALTER TABLE %s RESET (%s);", tableName, strings.Join(reset, ", ")))
+	}
+
+	if oldKey, newKey := partitionKey(old), partitionKey(ent); oldKey != newKey {
+		manual(fmt.Sprintf("the partitioning of %s changes from %s to %s, which PostgreSQL cannot alter; recreate the table and copy its rows",
+			ent.GetQualifiedTableName(), oldKey, newKey))
+		return
+	}
+	if ent.PartitionBy == nil {
+		return
+	}
+
+	oldBounds, newBounds := partitionBounds(old), partitionBounds(ent)
+	for _, partition := range old.Partitions {
+		if bound, ok := newBounds[partition.Name]; !ok || bound != partition.Bound {
+			name := entity.QualifiedName(old.Schema, partition.Name)
+			destroy(fmt.Sprintf("DROP TABLE %s;", entity.QuoteQualifiedName(name)),
+				fmt.Sprintf("drops partition %s and its data", name))
+		}
+	}
+	for _, partition := range ent.Partitions {
+		if bound, ok := oldBounds[partition.Name]; !ok || bound != partition.Bound {
+			name := entity.QuoteQualifiedName(entity.QualifiedName(ent.Schema, partition.Name))
+			add(fmt.Sprintf("CREATE TABLE %s PARTITION OF %s %s;", name, tableName, partition.Bound))
+		}
+	}
+}
+
+// diffComments renders the COMMENT statements changing the comments of old
+// and its columns to those of ent. A removed comment is set to NULL; the
+// comments of dropped columns go with them.
+func (g *SchemaGenerator) diffComments(old, ent *entity.Entity) string {
+	tableName := g.getTableName(ent)
+	var sb strings.Builder
+	if old.Comment != ent.Comment {
+		sb.WriteString(fmt.Sprintf("COMMENT ON %s %s IS %s;\n", g.objectKind(ent), tableName, commentLiteral(ent.Comment)))
+	}
+	for _, field := range ent.GetGenerateableFields() {
+		oldField, _ := findColumn(old, field.ColumnName())
+		if oldField.Comment != field.Comment {
+			sb.WriteString(fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;\n",
+				tableName, pq.QuoteIdentifier(field.ColumnName()), commentLiteral(field.Comment)))
+		}
+	}
+	return sb.String()
+}
+
+// commentLiteral quotes comment for COMMENT ON, or returns NULL to remove it.
+func commentLiteral(comment string) string {
+	if comment == "" {
+		return "NULL"
+	}
+	return quoteLiteral(comment)
+}
+
+// staleViews returns the qualified names of the views in both versions of
+// the model that have to be dropped and created again: those whose query
+// changes, and those selecting from a table one of whose columns is dropped,
+// changes type or gets another generation expression, which PostgreSQL
+// refuses while a view depends on it. A view selecting from a stale view is
+// stale too. Dependencies are found by the table and view names a query
+// mentions.
+func (g *SchemaGenerator) staleViews(from, to []*entity.Entity, pairs []tablePair) map[string]bool {
+	var altered []string
+	for _, pair := range pairs {
+		if g.columnsAltered(pair.from, pair.to) {
+			altered = append(altered, pair.to.GetTableName())
+		}
+	}
+
+	stale := make(map[string]bool)
+	for changed := true; changed; {
+		changed = false
+		for _, view := range to {
+			if !view.View || stale[view.GetQualifiedTableName()] {
+				continue
+			}
+			old := findQualified(from, view)
+			if old == nil {
+				continue
+			}
+			if !sameView(old, view) || len(mentionedColumns(old.ViewSQL, altered)) > 0 {
+				stale[view.GetQualifiedTableName()] = true
+				altered = append(altered, view.GetTableName())
+				changed = true
+			}
+		}
+	}
+	return stale
+}
+
+// columnsAltered reports whether a column of old is dropped, changes type
+// or gets another generation expression in ent.
+func (g *SchemaGenerator) columnsAltered(old, ent *entity.Entity) bool {
+	for _, oldField := range old.GetGenerateableFields() {
+		field, ok := findColumn(ent, oldField.ColumnName())
+		if !ok || g.columnType(oldField) != g.columnType(field) || oldField.GeneratedExpr != field.GeneratedExpr {
+			return true
+		}
+	}
+	return false
+}
+
+// storageParams maps the storage parameters of ent by name.
+func storageParams(ent *entity.Entity) map[string]string {
+	params := make(map[string]string, len(ent.StorageParams))
+	for _, param := range ent.StorageParams {
+		params[storageParamName(param)] = param
+	}
+	return params
+}
+
+// storageParamName returns the name of a name=value storage parameter.
+func storageParamName(param string) string {
+	name, _, _ := strings.Cut(param, "=")
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// partitionKey describes how ent is partitioned, such as RANGE (created_at),
+// or returns "no partitioning".
+func partitionKey(ent *entity.Entity) string {
+	if ent.PartitionBy == nil {
+		return "no partitioning"
+	}
+	return fmt.Sprintf("%s (%s)", strings.ToUpper(ent.PartitionBy.Method), strings.Join(ent.PartitionBy.Columns, ", "))
+}
+
+// partitionBounds maps the partitions of ent by name to their bounds.
+func partitionBounds(ent *entity.Entity) map[string]string {
+	bounds := make(map[string]string, len(ent.Partitions))
+	for _, partition := range ent.Partitions {
+		bounds[partition.Name] = partition.Bound
+	}
+	return bounds
+}
+
+// columnDefinition renders a column for ADD COLUMN without its constraints,
+// which the migration adds by name.
+func (g *SchemaGenerator) columnDefinition(field entity.Field) string {
+	def := pq.QuoteIdentifier(field.ColumnName()) + " " + g.columnType(field)
+	if field.Identity == "identity" {
+		def += " GENERATED ALWAYS AS IDENTITY"
+	}
+	if !g.nullable(field) {
+		def += " NOT NULL"
+	}
+	if field.DefaultVal != "" {
+		def += " DEFAULT " + field.DefaultVal
+	}
+	if field.GeneratedExpr != "" {
+		def += fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", field.GeneratedExpr)
+	}
+	return def
+}
+
+func (g *SchemaGenerator) columnType(field entity.Field) string {
+	mapping := g.mapper.MapFieldType(field.Type, field.UnderlyingType)
+	return g.mapper.ColumnType(mapping, g.getFieldTags(field))
+}
+
+func (g *SchemaGenerator) nullable(field entity.Field) bool {
+	mapping := g.mapper.MapFieldType(field.Type, field.UnderlyingType)
+	return g.mapper.Nullable(mapping, g.getFieldTags(field))
+}
+
+// tableConstraints returns the constraints generateTable creates for ent,
+// named in the same order.
+func (g *SchemaGenerator) tableConstraints(ent *entity.Entity) ([]tableConstraint, error) {
+	names := g.newConstraintNamer(ent)
+	var constraints []tableConstraint
+	var err error
+	push := func(name, definition string, foreignKey bool) {
+		if name == "" && err == nil {
+			err = fmt.Errorf("table %s: %s: %w", ent.GetQualifiedTableName(), definition, ErrUnnamedConstraint)
+		}
+		constraints = append(constraints, tableConstraint{name: name, definition: definition, foreignKey: foreignKey})
+	}
+
+	composite := ent.HasCompositePrimaryKey()
+	fields := ent.GetGenerateableFields()
+	for _, field := range fields {
+		column := pq.QuoteIdentifier(field.ColumnName())
+		if field.IsPrimary && !composite {
+			push(names.primaryKey(), fmt.Sprintf("PRIMARY KEY (%s)", column), false)
+		}
+		if field.IsUnique && field.IndexGroup == "" {
+			push(names.unique(field.ColumnName()), fmt.Sprintf("UNIQUE (%s)", column), false)
+		}
+		mapping := g.mapper.MapFieldType(field.Type, field.UnderlyingType)
+		if len(mapping.Constraints) > 0 {
+			push(names.check(field.ColumnName()), strings.ReplaceAll(mapping.Constraints[0], "{column}", column), false)
+		}
+		if field.CheckExpr != "" {
+			push(names.check(field.ColumnName()), fmt.Sprintf("CHECK (%s)", field.CheckExpr), false)
+		}
+		if len(field.EnumValues) > 0 {
+			push(names.check(field.ColumnName()), g.enumCheck(field), false)
+		}
+		if field.FKReference != nil && field.FKGroup == "" {
+			reference := g.foreignKeyReference([]string{column}, field.FKReference.Table,
+				[]string{pq.QuoteIdentifier(field.FKReference.Column)}, field.FKOnDelete, field.FKOnUpdate)
+			push(names.foreignKey(field.ColumnName()), "FOREIGN KEY "+reference, true)
+		}
+	}
+
+	if composite {
+		var columns []string
+		for _, field := range ent.GetPrimaryKeyFields() {
+			if field.ShouldGenerate() {
+				columns = append(columns, pq.QuoteIdentifier(field.ColumnName()))
+			}
+		}
+		if len(columns) > 1 {
+			push(names.primaryKey(), fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(columns, ", ")), false)
+		}
+	}
+
+	uniqueConstraints := ent.GetUniqueConstraints()
+	for _, groupName := range groupNames(ent.Fields, uniqueConstraints, func(field entity.Field) string {
+		return field.IndexGroup
+	}) {
+		var columns, quoted []string
+		for _, field := range uniqueConstraints[groupName] {
+			if field.ShouldGenerate() {
+				columns = append(columns, field.ColumnName())
+				quoted = append(quoted, pq.QuoteIdentifier(field.ColumnName()))
+			}
+		}
+		if len(uniqueConstraints[groupName]) < 2 || len(quoted) < 2 {
+			continue
+		}
+		name := groupName
+		if !strings.HasPrefix(groupName, "uq_") {
+			name = names.unique(columns...)
+		}
+		push(name, fmt.Sprintf("UNIQUE (%s)", strings.Join(quoted, ", ")), false)
+	}
+
+	fkGroups := g.groupFieldsByFK(fields)
+	for _, groupName := range groupNames(fields, fkGroups, fkGroupKey) {
+		if len(fkGroups[groupName]) < 2 {
+			continue
+		}
+		if _, reference, ok := g.compositeForeignKey(fkGroups[groupName]); ok {
+			push(groupName, "FOREIGN KEY "+reference, true)
+		}
+	}
+
+	for _, check := range ent.Checks {
+		push(names.tableCheck(check), fmt.Sprintf("CHECK (%s)", check), false)
+	}
+	for _, exclusion := range ent.Exclusions {
+		push(names.exclusion(exclusion), "EXCLUDE "+exclusion, false)
+	}
+
+	return constraints, err
+}
+
+// tableIndexes returns the CREATE INDEX statement of each of ent's indexes
+// by name.
+func (g *SchemaGenerator) tableIndexes(ent *entity.Entity) map[string]string {
+	groups := g.groupFieldsByIndex(ent.Fields)
+	indexes := make(map[string]string, len(groups))
+	for name, fields := range groups {
+		indexes[name] = strings.TrimSpace(g.generateIndex(name, g.getTableName(ent), fields, SchemaOptions{}))
+	}
+	return indexes
+}
+
+// indexNames returns the names of ent's indexes in declaration order.
+func (g *SchemaGenerator) indexNames(ent *entity.Entity) []string {
+	return groupNames(ent.Fields, g.groupFieldsByIndex(ent.Fields), func(field entity.Field) string {
+		return field.IndexName
+	})
+}
+
+// changedConstraints returns the constraints of a that b lacks or defines
+// differently.
+func changedConstraints(a, b []tableConstraint) []tableConstraint {
+	definitions := make(map[string]string, len(b))
+	for _, c := range b {
+		definitions[c.name] = c.definition
+	}
+	var changed []tableConstraint
+	for _, c := range a {
+		if definition, ok := definitions[c.name]; !ok || definition != c.definition {
+			changed = append(changed, c)
+		}
+	}
+	return changed
+}
+
+// findQualified returns the entity among entities stored under the same
+// schema-qualified name as ent, or nil.
+func findQualified(entities []*entity.Entity, ent *entity.Entity) *entity.Entity {
+	for _, other := range entities {
+		if other.View == ent.View && other.GetQualifiedTableName() == ent.GetQualifiedTableName() {
+			return other
+		}
+	}
+	return nil
+}
+
+// findColumn returns the generated field of ent stored in column.
+func findColumn(ent *entity.Entity, column string) (entity.Field, bool) {
+	for _, field := range ent.GetGenerateableFields() {
+		if field.ColumnName() == column {
+			return field, true
+		}
+	}
+	return entity.Field{}, false
+}
+
+func sameView(a, b *entity.Entity) bool {
+	return a.Materialized == b.Materialized && strings.TrimSpace(a.ViewSQL) == strings.TrimSpace(b.ViewSQL)
+}
+
+// missing returns the names in want that have is lacking.
+func missing(have, want []string) []string {
+	seen := make(map[string]bool, len(have))
+	for _, name := range have {
+		seen[name] = true
+	}
+	var names []string
+	for _, name := range want {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
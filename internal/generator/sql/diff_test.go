@@ -0,0 +1,613 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/n0xum/structify/internal/domain/entity"
+)
+
+func diffModels() (from, to []*entity.Entity) {
+	from = []*entity.Entity{
+		{
+			Name: "Customer",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Email", Type: "string"},
+				{Name: "Nickname", Type: "*string"},
+				{Name: "Fax", Type: "*string"},
+			},
+		},
+		{
+			Name: "Order",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "CustomerID", Type: "int64", FKReference: &entity.FKReference{Table: "customer", Column: "id"}},
+				{Name: "Total", Type: "int32", CheckExpr: "total > 0"},
+				{Name: "Status", Type: "string", DefaultVal: "'new'", IndexName: "idx_order_status"},
+			},
+		},
+		{
+			Name: "Coupon",
+			Fields: []entity.Field{
+				{Name: "Code", Type: "string", IsPrimary: true},
+			},
+		},
+	}
+	to = []*entity.Entity{
+		{
+			Name: "Customer",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Email", Type: "string", IsUnique: true},
+				{Name: "Nickname", Type: "string"},
+				{Name: "Score", Type: "int32", DefaultVal: "0"},
+			},
+		},
+		{
+			Name: "Order",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "CustomerID", Type: "int64", FKReference: &entity.FKReference{Table: "customer", Column: "id"}, FKOnDelete: "CASCADE"},
+				{Name: "Total", Type: "int64", CheckExpr: "total >= 0"},
+				{Name: "Status", Type: "string", IndexName: "idx_order_status", IndexDesc: true},
+				{Name: "ShipmentID", Type: "*int64", FKReference: &entity.FKReference{Table: "shipment", Column: "id"}},
+			},
+		},
+		{
+			Name: "Shipment",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Carrier", Type: "string"},
+			},
+		},
+	}
+	return from, to
+}
+
+func TestSchemaGeneratorDiff(t *testing.T) {
+	gen := NewSchemaGenerator()
+	from, to := diffModels()
+
+	migration, err := gen.Diff(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	wantUp := `ALTER TABLE "order" DROP CONSTRAINT "fk_order_customer_id";
+
+ALTER TABLE "order" DROP CONSTRAINT "ck_order_total";
+
+DROP INDEX "idx_order_status";
+
+CREATE TABLE "shipment" (
+    "id" BIGINT CONSTRAINT "pk_shipment" PRIMARY KEY,
+    "carrier" VARCHAR(255) NOT NULL
+);
+
+-- WARNING: customer.nickname becomes NOT NULL, which fails if it holds NULLs
+ALTER TABLE "customer" ALTER COLUMN "nickname" SET NOT NULL;
+
+ALTER TABLE "customer" ADD COLUMN "score" INTEGER NOT NULL DEFAULT 0;
+
+-- DESTRUCTIVE: changing order.total from INTEGER to BIGINT can fail or lose data
+ALTER TABLE "order" ALTER COLUMN "total" TYPE BIGINT USING "total"::BIGINT;
+
+ALTER TABLE "order" ALTER COLUMN "status" DROP DEFAULT;
+
+ALTER TABLE "order" ADD COLUMN "shipment_id" BIGINT;
+
+ALTER TABLE "customer" ADD CONSTRAINT "uq_customer_email" UNIQUE ("email");
+
+ALTER TABLE "order" ADD CONSTRAINT "ck_order_total" CHECK (total >= 0);
+
+ALTER TABLE "order" ADD CONSTRAINT "fk_order_customer_id" FOREIGN KEY ("customer_id") REFERENCES "customer" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "order" ADD CONSTRAINT "fk_order_shipment_id" FOREIGN KEY ("shipment_id") REFERENCES "shipment" ("id");
+
+CREATE INDEX "idx_order_status" ON "order" ("status" DESC);
+
+-- DESTRUCTIVE: drops column customer.fax and its data
+ALTER TABLE "customer" DROP COLUMN "fax";
+
+-- DESTRUCTIVE: drops table coupon and its data
+DROP TABLE "coupon";
+
+`
+	if got := migration.UpSQL(); got != wantUp {
+		t.Errorf("UpSQL() =\n%s\nwant\n%s", got, wantUp)
+	}
+
+	down := migration.DownSQL()
+	for _, want := range []string{
+		`ALTER TABLE "order" DROP CONSTRAINT "fk_order_shipment_id";`,
+		`ALTER TABLE "customer" DROP CONSTRAINT "uq_customer_email";`,
+		`CREATE TABLE "coupon" (`,
+		`ALTER TABLE "customer" ALTER COLUMN "nickname" DROP NOT NULL;`,
+		`ALTER TABLE "customer" ADD COLUMN "fax" VARCHAR(255);`,
+		`ALTER TABLE "order" ALTER COLUMN "status" SET DEFAULT 'new';`,
+		`ALTER TABLE "order" ADD CONSTRAINT "ck_order_total" CHECK (total > 0);`,
+		`CREATE INDEX "idx_order_status" ON "order" ("status");`,
+		"-- DESTRUCTIVE: drops table shipment and its data\nDROP TABLE \"shipment\";",
+	} {
+		if !strings.Contains(down, want) {
+			t.Errorf("DownSQL() missing %q:\n%s", want, down)
+		}
+	}
+	// The foreign key to shipment must go before the table
+	if strings.Index(down, `DROP CONSTRAINT "fk_order_shipment_id"`) > strings.Index(down, `DROP TABLE "shipment"`) {
+		t.Errorf("DownSQL() drops shipment before the foreign key referencing it:\n%s", down)
+	}
+}
+
+func TestSchemaGeneratorDiffDestructiveSteps(t *testing.T) {
+	gen := NewSchemaGenerator()
+	from, to := diffModels()
+
+	migration, err := gen.Diff(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	var destructive []string
+	for _, step := range migration.Up {
+		if step.Destructive {
+			destructive = append(destructive, step.SQL)
+		}
+	}
+	want := []string{
+		`ALTER TABLE "order" ALTER COLUMN "total" TYPE BIGINT USING "total"::BIGINT;`,
+		`ALTER TABLE "customer" DROP COLUMN "fax";`,
+		`DROP TABLE "coupon";`,
+	}
+	if strings.Join(destructive, "\n") != strings.Join(want, "\n") {
+		t.Errorf("destructive steps = %v, want %v", destructive, want)
+	}
+}
+
+func TestSchemaGeneratorDiffUnchanged(t *testing.T) {
+	gen := NewSchemaGenerator()
+	_, to := diffModels()
+
+	migration, err := gen.Diff(context.Background(), to, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if migration.HasChanges() {
+		t.Errorf("HasChanges() = true, want false; up:\n%s", migration.UpSQL())
+	}
+	if len(migration.Down) != 0 {
+		t.Errorf("Down = %v, want no steps", migration.Down)
+	}
+}
+
+func TestSchemaGeneratorDiffSchemasAndViews(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	table := &entity.Entity{
+		Name: "Invoice",
+		Fields: []entity.Field{
+			{Name: "ID", Type: "int64", IsPrimary: true},
+			{Name: "Amount", Type: "int64"},
+		},
+	}
+	from := []*entity.Entity{
+		table,
+		{Name: "InvoiceTotal", View: true, ViewSQL: "SELECT sum(amount) FROM invoice"},
+	}
+	to := []*entity.Entity{
+		table,
+		{Name: "InvoiceTotal", View: true, ViewSQL: "SELECT sum(amount) AS total FROM invoice"},
+		{
+			Name:   "Ledger",
+			Schema: "billing",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "uuid.UUID", IsPrimary: true},
+			},
+		},
+	}
+
+	migration, err := gen.Diff(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	up := migration.UpSQL()
+	var last int
+	for _, want := range []string{
+		`CREATE SCHEMA IF NOT EXISTS "billing";`,
+		`DROP VIEW "invoice_total";`,
+		`CREATE TABLE "billing"."ledger" (`,
+		`CREATE VIEW "invoice_total" AS`,
+	} {
+		i := strings.Index(up, want)
+		if i < last {
+			t.Errorf("UpSQL() missing %q or out of order:\n%s", want, up)
+			continue
+		}
+		last = i
+	}
+
+	down := migration.DownSQL()
+	if !strings.Contains(down, `DROP TABLE "billing"."ledger";`) {
+		t.Errorf("DownSQL() missing DROP TABLE:\n%s", down)
+	}
+	if strings.Contains(down, "DROP SCHEMA") {
+		t.Errorf("DownSQL() drops a schema:\n%s", down)
+	}
+}
+
+func TestSchemaGeneratorDiffUnnamedConstraints(t *testing.T) {
+	gen := NewSchemaGenerator()
+	gen.SetNamingStrategy(PostgresNaming{})
+	from, to := diffModels()
+
+	_, err := gen.Diff(context.Background(), from, to)
+	if !errors.Is(err, ErrUnnamedConstraint) {
+		t.Errorf("Diff() error = %v, want %v", err, ErrUnnamedConstraint)
+	}
+}
+
+func TestSchemaGeneratorDiffTableLevelConstraints(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	booking := func(checks, exclusions []string) []*entity.Entity {
+		return []*entity.Entity{
+			{
+				Name:       "Booking",
+				Checks:     checks,
+				Exclusions: exclusions,
+				Fields: []entity.Field{
+					{Name: "ID", Type: "int64", IsPrimary: true},
+					{Name: "RoomID", Type: "int64"},
+					{Name: "Guests", Type: "int32"},
+					{Name: "During", Type: "pgtype.Range[time.Time]"},
+				},
+			},
+		}
+	}
+	exclusion := "USING gist (room_id WITH =, during WITH &&)"
+	// Scalar equality under GiST brings in btree_gist first
+	addExclusion := `CREATE EXTENSION IF NOT EXISTS "btree_gist";` + "\n\n" +
+		`ALTER TABLE "booking" ADD CONSTRAINT "ex_booking_room_id_during" EXCLUDE ` + exclusion + ";\n\n"
+	dropExclusion := `ALTER TABLE "booking" DROP CONSTRAINT "ex_booking_room_id_during";` + "\n\n"
+	guestsCheck := "ck_booking_guests_" + hashName("guests < 10")
+
+	tests := []struct {
+		name     string
+		from, to []*entity.Entity
+		wantUp   string
+		wantDown string
+	}{
+		{
+			name:     "exclusion added",
+			from:     booking([]string{"guests > 0"}, nil),
+			to:       booking([]string{"guests > 0"}, []string{exclusion}),
+			wantUp:   addExclusion,
+			wantDown: dropExclusion,
+		},
+		{
+			name:     "exclusion removed",
+			from:     booking(nil, []string{exclusion}),
+			to:       booking(nil, nil),
+			wantUp:   dropExclusion,
+			wantDown: addExclusion,
+		},
+		{
+			name:     "check inserted before another",
+			from:     booking([]string{"guests > 0"}, nil),
+			to:       booking([]string{"room_id > 0", "guests > 0"}, nil),
+			wantUp:   `ALTER TABLE "booking" ADD CONSTRAINT "ck_booking_room_id" CHECK (room_id > 0);` + "\n\n",
+			wantDown: `ALTER TABLE "booking" DROP CONSTRAINT "ck_booking_room_id";` + "\n\n",
+		},
+		{
+			name:     "check on a column with another check",
+			from:     booking([]string{"guests > 0"}, nil),
+			to:       booking([]string{"guests > 0", "guests < 10"}, nil),
+			wantUp:   `ALTER TABLE "booking" ADD CONSTRAINT "` + guestsCheck + `" CHECK (guests < 10);` + "\n\n",
+			wantDown: `ALTER TABLE "booking" DROP CONSTRAINT "` + guestsCheck + `";` + "\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration, err := gen.Diff(context.Background(), tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if got := migration.UpSQL(); got != tt.wantUp {
+				t.Errorf("UpSQL() = %q, want %q", got, tt.wantUp)
+			}
+			if got := migration.DownSQL(); got != tt.wantDown {
+				t.Errorf("DownSQL() = %q, want %q", got, tt.wantDown)
+			}
+		})
+	}
+}
+
+func TestSchemaGeneratorDiffTableOptions(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	event := func(change func(*entity.Entity)) []*entity.Entity {
+		ent := &entity.Entity{
+			Name: "Event",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Region", Type: "string"},
+				{Name: "CreatedAt", Type: "time.Time"},
+			},
+		}
+		if change != nil {
+			change(ent)
+		}
+		return []*entity.Entity{ent}
+	}
+	byMonth := func(partitions ...entity.Partition) func(*entity.Entity) {
+		return func(ent *entity.Entity) {
+			ent.PartitionBy = &entity.Partitioning{Method: "RANGE", Columns: []string{"created_at"}}
+			ent.Partitions = partitions
+		}
+	}
+	january := entity.Partition{Name: "event_2026_01", Bound: "FOR VALUES FROM ('2026-01-01') TO ('2026-02-01')"}
+	february := entity.Partition{Name: "event_2026_02", Bound: "FOR VALUES FROM ('2026-02-01') TO ('2026-03-01')"}
+	longJanuary := entity.Partition{Name: "event_2026_01", Bound: "FOR VALUES FROM ('2026-01-01') TO ('2026-02-15')"}
+
+	tests := []struct {
+		name     string
+		from, to []*entity.Entity
+		wantUp   string
+		wantDown string
+	}{
+		{
+			name:     "unlogged",
+			from:     event(nil),
+			to:       event(func(ent *entity.Entity) { ent.Unlogged = true }),
+			wantUp:   `ALTER TABLE "event" SET UNLOGGED;` + "\n\n",
+			wantDown: `ALTER TABLE "event" SET LOGGED;` + "\n\n",
+		},
+		{
+			name: "storage parameters",
+			from: event(func(ent *entity.Entity) { ent.StorageParams = []string{"fillfactor=70", "autovacuum_enabled=false"} }),
+			to:   event(func(ent *entity.Entity) { ent.StorageParams = []string{"fillfactor=90", "toast_tuple_target=256"} }),
+			wantUp: `ALTER TABLE "event" SET (fillfactor=90, toast_tuple_target=256);` + "\n\n" +
+				`ALTER TABLE "event" RESET (autovacuum_enabled);` + "\n\n",
+			wantDown: `ALTER TABLE "event" SET (fillfactor=70, autovacuum_enabled=false);` + "\n\n" +
+				`ALTER TABLE "event" RESET (toast_tuple_target);` + "\n\n",
+		},
+		{
+			name:     "partition key",
+			from:     event(nil),
+			to:       event(byMonth(january)),
+			wantUp:   "-- WARNING: the partitioning of event changes from no partitioning to RANGE (created_at), which PostgreSQL cannot alter; recreate the table and copy its rows\n\n",
+			wantDown: "-- WARNING: the partitioning of event changes from RANGE (created_at) to no partitioning, which PostgreSQL cannot alter; recreate the table and copy its rows\n\n",
+		},
+		{
+			name:   "partition added",
+			from:   event(byMonth(january)),
+			to:     event(byMonth(january, february)),
+			wantUp: `CREATE TABLE "event_2026_02" PARTITION OF "event" FOR VALUES FROM ('2026-02-01') TO ('2026-03-01');` + "\n\n",
+			wantDown: "-- DESTRUCTIVE: drops partition event_2026_02 and its data\n" +
+				`DROP TABLE "event_2026_02";` + "\n\n",
+		},
+		{
+			name: "partition bound",
+			from: event(byMonth(january)),
+			to:   event(byMonth(longJanuary)),
+			wantUp: "-- DESTRUCTIVE: drops partition event_2026_01 and its data\n" +
+				`DROP TABLE "event_2026_01";` + "\n\n" +
+				`CREATE TABLE "event_2026_01" PARTITION OF "event" FOR VALUES FROM ('2026-01-01') TO ('2026-02-15');` + "\n\n",
+			wantDown: "-- DESTRUCTIVE: drops partition event_2026_01 and its data\n" +
+				`DROP TABLE "event_2026_01";` + "\n\n" +
+				`CREATE TABLE "event_2026_01" PARTITION OF "event" FOR VALUES FROM ('2026-01-01') TO ('2026-02-01');` + "\n\n",
+		},
+		{
+			name: "comments",
+			from: event(func(ent *entity.Entity) { ent.Fields[1].Comment = "ISO region" }),
+			to: event(func(ent *entity.Entity) {
+				ent.Comment = "Tracked events"
+				ent.Fields[2].Comment = "When it happened"
+			}),
+			wantUp: `COMMENT ON TABLE "event" IS 'Tracked events';` + "\n" +
+				`COMMENT ON COLUMN "event"."region" IS NULL;` + "\n" +
+				`COMMENT ON COLUMN "event"."created_at" IS 'When it happened';` + "\n\n",
+			wantDown: `COMMENT ON TABLE "event" IS NULL;` + "\n" +
+				`COMMENT ON COLUMN "event"."region" IS 'ISO region';` + "\n" +
+				`COMMENT ON COLUMN "event"."created_at" IS NULL;` + "\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration, err := gen.Diff(context.Background(), tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if got := migration.UpSQL(); got != tt.wantUp {
+				t.Errorf("UpSQL() = %q, want %q", got, tt.wantUp)
+			}
+			if got := migration.DownSQL(); got != tt.wantDown {
+				t.Errorf("DownSQL() = %q, want %q", got, tt.wantDown)
+			}
+		})
+	}
+}
+
+func TestSchemaGeneratorDiffGeneratedColumns(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	person := func(expr string) []*entity.Entity {
+		return []*entity.Entity{
+			{
+				Name: "Person",
+				Fields: []entity.Field{
+					{Name: "ID", Type: "int64", IsPrimary: true},
+					{Name: "Email", Type: "string"},
+					{Name: "Login", Type: "string", GeneratedExpr: expr},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		from   []*entity.Entity
+		to     []*entity.Entity
+		wantUp string
+	}{
+		{
+			name: "expression changed",
+			from: person("lower(email)"),
+			to:   person("upper(email)"),
+			wantUp: "-- WARNING: person.login gets a new generation expression, which needs PostgreSQL 17 and rewrites the table\n" +
+				`ALTER TABLE "person" ALTER COLUMN "login" SET EXPRESSION AS (upper(email));` + "\n\n",
+		},
+		{
+			name:   "expression dropped",
+			from:   person("lower(email)"),
+			to:     person(""),
+			wantUp: `ALTER TABLE "person" ALTER COLUMN "login" DROP EXPRESSION;` + "\n\n",
+		},
+		{
+			name:   "column becomes generated",
+			from:   person(""),
+			to:     person("lower(email)"),
+			wantUp: "-- WARNING: person.login becomes a generated column, which PostgreSQL cannot alter; drop the column and add it again\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration, err := gen.Diff(context.Background(), tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if got := migration.UpSQL(); got != tt.wantUp {
+				t.Errorf("UpSQL() = %q, want %q", got, tt.wantUp)
+			}
+		})
+	}
+}
+
+func TestSchemaGeneratorDiffStaleViews(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	model := func(amountType string, withNote bool) []*entity.Entity {
+		fields := []entity.Field{
+			{Name: "ID", Type: "int64", IsPrimary: true},
+			{Name: "Amount", Type: amountType},
+		}
+		if withNote {
+			fields = append(fields, entity.Field{Name: "Note", Type: "*string"})
+		}
+		return []*entity.Entity{
+			{Name: "Payment", Fields: fields},
+			{Name: "Payout", Fields: []entity.Field{{Name: "ID", Type: "int64", IsPrimary: true}}},
+			{Name: "PaymentTotal", View: true, ViewSQL: "SELECT sum(amount) AS total FROM payment"},
+			{Name: "PaymentReport", View: true, Materialized: true, ViewSQL: `SELECT total FROM "payment_total"`},
+			{Name: "PayoutCount", View: true, ViewSQL: "SELECT count(*) FROM payout"},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		from, to []*entity.Entity
+		wantUp   []string
+	}{
+		{
+			name: "column type changed",
+			from: model("int32", false),
+			to:   model("int64", false),
+			wantUp: []string{
+				`DROP MATERIALIZED VIEW "payment_report";`,
+				`DROP VIEW "payment_total";`,
+				`ALTER TABLE "payment" ALTER COLUMN "amount" TYPE BIGINT`,
+				`CREATE VIEW "payment_total" AS`,
+				`CREATE MATERIALIZED VIEW "payment_report" AS`,
+			},
+		},
+		{
+			name: "column dropped",
+			from: model("int64", true),
+			to:   model("int64", false),
+			wantUp: []string{
+				`DROP MATERIALIZED VIEW "payment_report";`,
+				`DROP VIEW "payment_total";`,
+				`ALTER TABLE "payment" DROP COLUMN "note";`,
+				`CREATE VIEW "payment_total" AS`,
+				`CREATE MATERIALIZED VIEW "payment_report" AS`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration, err := gen.Diff(context.Background(), tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+
+			up := migration.UpSQL()
+			last := -1
+			for _, want := range tt.wantUp {
+				i := strings.Index(up, want)
+				if i <= last {
+					t.Errorf("UpSQL() missing %q or out of order:\n%s", want, up)
+					continue
+				}
+				last = i
+			}
+			if strings.Contains(up, "payout_count") {
+				t.Errorf("UpSQL() recreates a view of an unchanged table:\n%s", up)
+			}
+		})
+	}
+
+	t.Run("column added", func(t *testing.T) {
+		migration, err := gen.Diff(context.Background(), model("int64", false), model("int64", true))
+		if err != nil {
+			t.Fatalf("Diff() error = %v", err)
+		}
+		if up := migration.UpSQL(); strings.Contains(up, "VIEW") {
+			t.Errorf("UpSQL() recreates views for an added column:\n%s", up)
+		}
+	})
+}
+
+// The names Diff refers to must be the names Generate gives
+func TestSchemaGeneratorTableConstraintsMatchGenerate(t *testing.T) {
+	gen := NewSchemaGenerator()
+	entities := []*entity.Entity{
+		{
+			Name:       "Transfer",
+			Checks:     []string{"amount > 0", "kind <> 'in' OR amount < 500"},
+			Exclusions: []string{"USING gist (currency WITH =, day WITH =)"},
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Reference", Type: "string", IsUnique: true},
+				{Name: "Amount", Type: "uint32", CheckExpr: "amount < 1000000"},
+				{Name: "Kind", Type: "string", EnumValues: []string{"in", "out"}},
+				{Name: "Currency", Type: "string", IsUnique: true, IndexGroup: "currency_day"},
+				{Name: "Day", Type: "time.Time", IsUnique: true, IndexGroup: "currency_day"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	constraints, err := gen.tableConstraints(entities[0])
+	if err != nil {
+		t.Fatalf("tableConstraints() error = %v", err)
+	}
+	if len(constraints) != 9 {
+		t.Errorf("tableConstraints() returned %d constraints, want 9", len(constraints))
+	}
+	for _, c := range constraints {
+		if want := `CONSTRAINT "` + c.name + `"`; !strings.Contains(result, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, result)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package sql
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lib/pq"
+	"github.com/n0xum/structify/internal/domain/entity"
+)
+
+// maxIdentifierLength is PostgreSQL's limit on identifiers, in bytes
+// (NAMEDATALEN - 1). Longer names are silently truncated by the server.
+const maxIdentifierLength = 63
+
+// NamingStrategy names the constraints SchemaGenerator creates. table is
+// the bare table name and columns the column names in declaration order;
+// for a table-level CHECK or an EXCLUDE constraint they are the columns its
+// definition mentions. An empty name leaves the constraint to PostgreSQL's
+// automatic naming.
+type NamingStrategy interface {
+	PrimaryKey(table string) string
+	ForeignKey(table string, columns []string) string
+	Check(table string, columns []string) string
+	Unique(table string, columns []string) string
+	Exclusion(table string, columns []string) string
+}
+
+// ConventionalNaming names constraints pk_<table>, fk_<table>_<columns>,
+// ck_<table>_<columns>, uq_<table>_<columns> and ex_<table>_<columns>. It
+// is the default.
+type ConventionalNaming struct{}
+
+func (ConventionalNaming) PrimaryKey(table string) string {
+	return "pk_" + table
+}
+
+func (ConventionalNaming) ForeignKey(table string, columns []string) string {
+	return joinName("fk", table, columns)
+}
+
+func (ConventionalNaming) Check(table string, columns []string) string {
+	return joinName("ck", table, columns)
+}
+
+func (ConventionalNaming) Unique(table string, columns []string) string {
+	return joinName("uq", table, columns)
+}
+
+func (ConventionalNaming) Exclusion(table string, columns []string) string {
+	return joinName("ex", table, columns)
+}
+
+// PostgresNaming leaves every constraint unnamed, so PostgreSQL picks
+// names such as user_pkey and order_user_id_fkey.
+type PostgresNaming struct{}
+
+func (PostgresNaming) PrimaryKey(string) string           { return "" }
+func (PostgresNaming) ForeignKey(string, []string) string { return "" }
+func (PostgresNaming) Check(string, []string) string      { return "" }
+func (PostgresNaming) Unique(string, []string) string     { return "" }
+func (PostgresNaming) Exclusion(string, []string) string  { return "" }
+
+func joinName(prefix, table string, columns []string) string {
+	return strings.Join(append([]string{prefix, table}, columns...), "_")
+}
+
+// limitIdentifier keeps name within maxIdentifierLength. A longer name is
+// cut and ends in a hash of the full name, so that two long names sharing
+// a prefix still differ.
+func limitIdentifier(name string) string {
+	if len(name) <= maxIdentifierLength {
+		return name
+	}
+	suffix := "_" + hashName(name)
+	cut := maxIdentifierLength - len(suffix)
+	for cut > 0 && !utf8.RuneStart(name[cut]) {
+		cut--
+	}
+	return name[:cut] + suffix
+}
+
+// hashName returns a short hash of s for telling names apart.
+func hashName(s string) string {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// constraintNamer hands out the constraint names of one table. It enforces
+// the identifier limit and keeps the names distinct from each other and
+// from the table's indexes, numbering repeats: ck_event, ck_event_2.
+// Table-level constraints are told apart by their definitions instead.
+type constraintNamer struct {
+	strategy NamingStrategy
+	table    string
+	columns  []string
+	used     map[string]bool
+}
+
+func (g *SchemaGenerator) newConstraintNamer(ent *entity.Entity) *constraintNamer {
+	// Names chosen by the user are taken before any is handed out
+	used := make(map[string]bool)
+	for _, field := range ent.Fields {
+		for _, name := range []string{field.IndexName, field.FKGroup, field.IndexGroup} {
+			if name != "" {
+				used[name] = true
+			}
+		}
+	}
+	var columns []string
+	for _, field := range ent.GetGenerateableFields() {
+		columns = append(columns, field.ColumnName())
+	}
+	return &constraintNamer{strategy: g.naming, table: ent.GetTableName(), columns: columns, used: used}
+}
+
+func (n *constraintNamer) primaryKey() string {
+	return n.claim(n.strategy.PrimaryKey(n.table))
+}
+
+func (n *constraintNamer) foreignKey(columns ...string) string {
+	return n.claim(n.strategy.ForeignKey(n.table, columns))
+}
+
+func (n *constraintNamer) check(columns ...string) string {
+	return n.claim(n.strategy.Check(n.table, columns))
+}
+
+func (n *constraintNamer) unique(columns ...string) string {
+	return n.claim(n.strategy.Unique(n.table, columns))
+}
+
+// tableCheck names the table-level CHECK constraint on expr after the
+// table's columns it mentions.
+func (n *constraintNamer) tableCheck(expr string) string {
+	return n.claimByDefinition(n.strategy.Check(n.table, mentionedColumns(expr, n.columns)), expr)
+}
+
+// exclusion names the EXCLUDE constraint defined by definition after the
+// table's columns it mentions.
+func (n *constraintNamer) exclusion(definition string) string {
+	return n.claimByDefinition(n.strategy.Exclusion(n.table, mentionedColumns(definition, n.columns)), definition)
+}
+
+// claimByDefinition is claim for a table-level constraint, whose position
+// among the others means nothing: a name already given out ends in a hash
+// of definition rather than a number, so that adding or removing one
+// constraint leaves the names of the rest alone.
+func (n *constraintNamer) claimByDefinition(name, definition string) string {
+	if name == "" {
+		return ""
+	}
+	if n.used[limitIdentifier(name)] {
+		name += "_" + hashName(definition)
+	}
+	return n.claim(name)
+}
+
+// claim limits name and reserves it for the table. A name already given
+// out gets the next free number appended.
+func (n *constraintNamer) claim(name string) string {
+	if name == "" {
+		return ""
+	}
+	candidate := limitIdentifier(name)
+	for i := 2; n.used[candidate]; i++ {
+		candidate = limitIdentifier(fmt.Sprintf("%s_%d", name, i))
+	}
+	n.used[candidate] = true
+	return candidate
+}
+
+// mentionedColumns returns the columns that appear in the SQL text expr as
+// identifiers, in the order of columns. String literals are skipped and
+// unquoted identifiers compared in lower case, as PostgreSQL folds them.
+func mentionedColumns(expr string, columns []string) []string {
+	seen := make(map[string]bool)
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == '\'' || c == '"':
+			// A doubled quote stands for the quote itself
+			end := i + 1
+			for end < len(expr) {
+				if expr[end] == c {
+					if end+1 < len(expr) && expr[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			if c == '"' {
+				seen[strings.ReplaceAll(expr[i+1:end], `""`, `"`)] = true
+			}
+			i = end + 1
+		case isIdentifierByte(c):
+			end := i
+			for end < len(expr) && (isIdentifierByte(expr[end]) || expr[end] >= '0' && expr[end] <= '9' || expr[end] == '$') {
+				end++
+			}
+			seen[strings.ToLower(expr[i:end])] = true
+			i = end
+		case c >= '0' && c <= '9':
+			// Skip numbers such as 1e5, which would read as identifier e5
+			for i < len(expr) && (isIdentifierByte(expr[i]) || expr[i] >= '0' && expr[i] <= '9' || expr[i] == '.') {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+
+	var mentioned []string
+	for _, column := range columns {
+		if seen[column] {
+			mentioned = append(mentioned, column)
+		}
+	}
+	return mentioned
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// constraintPrefix renders "CONSTRAINT name " for a named constraint, or
+// nothing when name is empty.
+func constraintPrefix(name string) string {
+	if name == "" {
+		return ""
+	}
+	return "CONSTRAINT " + pq.QuoteIdentifier(name) + " "
+}
@@ -0,0 +1,229 @@
+package sql
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/n0xum/structify/internal/domain/entity"
+)
+
+func TestLimitIdentifier(t *testing.T) {
+	long := "fk_" + strings.Repeat("warehouse_", 7) + "id"
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "short", in: "pk_user", want: "pk_user"},
+		{name: "exactly 63 bytes", in: strings.Repeat("a", 63), want: strings.Repeat("a", 63)},
+		{name: "hashed", in: long, want: long[:54] + "_f3ab7186"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := limitIdentifier(tt.in)
+			if got != tt.want {
+				t.Errorf("limitIdentifier() = %v, want %v", got, tt.want)
+			}
+			if len(got) > maxIdentifierLength {
+				t.Errorf("limitIdentifier() is %d bytes, want at most %d", len(got), maxIdentifierLength)
+			}
+		})
+	}
+
+	if limitIdentifier(long+"_a") == limitIdentifier(long+"_b") {
+		t.Errorf("limitIdentifier() gave two long names sharing a prefix the same name")
+	}
+	if got := limitIdentifier(strings.Repeat("ü", 40)); !utf8.ValidString(got) {
+		t.Errorf("limitIdentifier() = %q, cut inside a character", got)
+	}
+}
+
+func TestSchemaGeneratorConstraintNames(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Account",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+			},
+		},
+		{
+			Name:   "Transfer",
+			Checks: []string{"amount > 0", "source_id <> target_id"},
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Reference", Type: "string", IsUnique: true},
+				{Name: "SourceID", Type: "int64", FKReference: &entity.FKReference{Table: "account", Column: "id"}},
+				{Name: "TargetID", Type: "int64", FKReference: &entity.FKReference{Table: "account", Column: "id"}},
+				{Name: "Amount", Type: "uint32", CheckExpr: "amount < 1000000"},
+				{Name: "Currency", Type: "string", IsUnique: true, IndexGroup: "currency_day"},
+				{Name: "Day", Type: "time.Time", IsUnique: true, IndexGroup: "currency_day"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `CREATE TABLE "account" (
+    "id" BIGINT CONSTRAINT "pk_account" PRIMARY KEY
+);
+
+CREATE TABLE "transfer" (
+    "id" BIGINT CONSTRAINT "pk_transfer" PRIMARY KEY,
+    "reference" VARCHAR(255) NOT NULL CONSTRAINT "uq_transfer_reference" UNIQUE,
+    "source_id" BIGINT NOT NULL CONSTRAINT "fk_transfer_source_id" REFERENCES "account"("id"),
+    "target_id" BIGINT NOT NULL CONSTRAINT "fk_transfer_target_id" REFERENCES "account"("id"),
+    "amount" INTEGER NOT NULL CONSTRAINT "ck_transfer_amount" CHECK ("amount" >= 0) CONSTRAINT "ck_transfer_amount_2" CHECK (amount < 1000000),
+    "currency" VARCHAR(255) NOT NULL,
+    "day" TIMESTAMP NOT NULL,
+    CONSTRAINT "uq_transfer_currency_day" UNIQUE ("currency", "day"),
+    CONSTRAINT "ck_transfer_amount_4944c257" CHECK (amount > 0),
+    CONSTRAINT "ck_transfer_source_id_target_id" CHECK (source_id <> target_id)
+);
+
+`
+	if result != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", result, want)
+	}
+}
+
+func TestSchemaGeneratorConstraintNamesAreLimited(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	table := "warehouse_inventory_adjustment_" + strings.Repeat("x", 20)
+	entities := []*entity.Entity{
+		{
+			Name:      "Adjustment",
+			TableName: table,
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "ReasonCodeWithAVeryLongName", Type: "string", IsUnique: true},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	name := limitIdentifier("uq_" + table + "_reason_code_with_a_very_long_name")
+	if len(name) != maxIdentifierLength {
+		t.Fatalf("limitIdentifier() = %q, want %d bytes", name, maxIdentifierLength)
+	}
+	if want := `CONSTRAINT "` + name + `" UNIQUE`; !strings.Contains(result, want) {
+		t.Errorf("Generate() missing %q:\n%s", want, result)
+	}
+}
+
+func TestMentionedColumns(t *testing.T) {
+	columns := []string{"room_id", "during", "status", "Note"}
+
+	tests := []struct {
+		name string
+		expr string
+		want []string
+	}{
+		{name: "exclusion", expr: "USING gist (room_id WITH =, during WITH &&)", want: []string{"room_id", "during"}},
+		{name: "column order", expr: "USING gist (during WITH &&, room_id WITH =)", want: []string{"room_id", "during"}},
+		{name: "unquoted folds to lower case", expr: "USING gist (ROOM_ID WITH =)", want: []string{"room_id"}},
+		{name: "quoted keeps its case", expr: `"Note" <> '' AND "status" IS NOT NULL`, want: []string{"status", "Note"}},
+		{name: "string literals", expr: "note <> 'room_id' AND kind <> 'it''s during'", want: nil},
+		{name: "where clause", expr: "USING gist (during WITH &&) WHERE (status = 'active')", want: []string{"during", "status"}},
+		{name: "no columns", expr: "true", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mentionedColumns(tt.expr, columns)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("mentionedColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// suffixNaming puts the kind of constraint last, like PostgreSQL does.
+type suffixNaming struct{}
+
+func (suffixNaming) PrimaryKey(table string) string { return table + "_pkey" }
+func (suffixNaming) ForeignKey(table string, columns []string) string {
+	return table + "_" + strings.Join(columns, "_") + "_fkey"
+}
+func (suffixNaming) Check(table string, columns []string) string { return "" }
+func (suffixNaming) Unique(table string, columns []string) string {
+	return table + "_" + strings.Join(columns, "_") + "_key"
+}
+func (suffixNaming) Exclusion(table string, columns []string) string {
+	return table + "_" + strings.Join(columns, "_") + "_excl"
+}
+
+func TestSchemaGeneratorSetNamingStrategy(t *testing.T) {
+	entities := []*entity.Entity{
+		{
+			Name:       "Node",
+			Exclusions: []string{"USING gist (parent_id WITH =, span WITH &&)"},
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Slug", Type: "string", IsUnique: true},
+				{Name: "Weight", Type: "int", CheckExpr: "weight > 0"},
+				{Name: "ParentID", Type: "*int64", FKReference: &entity.FKReference{Table: "node", Column: "id"}},
+				{Name: "Span", Type: "pgtype.Range[int]"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		naming NamingStrategy
+		want   []string
+	}{
+		{
+			name:   "postgres",
+			naming: PostgresNaming{},
+			want: []string{
+				`"id" BIGINT PRIMARY KEY,`,
+				`"slug" VARCHAR(255) NOT NULL UNIQUE,`,
+				`"weight" INTEGER NOT NULL CHECK (weight > 0),`,
+				`    EXCLUDE USING gist (parent_id WITH =, span WITH &&)`,
+				`ALTER TABLE "node" ADD FOREIGN KEY ("parent_id") REFERENCES "node" ("id");`,
+			},
+		},
+		{
+			name:   "custom",
+			naming: suffixNaming{},
+			want: []string{
+				`"id" BIGINT CONSTRAINT "node_pkey" PRIMARY KEY,`,
+				`"slug" VARCHAR(255) NOT NULL CONSTRAINT "node_slug_key" UNIQUE,`,
+				`"weight" INTEGER NOT NULL CHECK (weight > 0),`,
+				`CONSTRAINT "node_parent_id_span_excl" EXCLUDE USING gist (parent_id WITH =, span WITH &&)`,
+				`ALTER TABLE "node" ADD CONSTRAINT "node_parent_id_fkey" FOREIGN KEY ("parent_id") REFERENCES "node" ("id");`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewSchemaGenerator()
+			gen.SetNamingStrategy(tt.naming)
+
+			result, err := gen.Generate(context.Background(), entities)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(result, want) {
+					t.Errorf("Generate() missing %q:\n%s", want, result)
+				}
+			}
+		})
+	}
+}
@@ -3,61 +3,139 @@ package sql
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/lib/pq"
 	"github.com/n0xum/structify/internal/domain/entity"
 	"github.com/n0xum/structify/internal/mapper"
-	"github.com/n0xum/structify/internal/util"
 )
 
 type SchemaGenerator struct {
 	mapper *mapper.Mapper
+	naming NamingStrategy
 }
 
 func NewSchemaGenerator() *SchemaGenerator {
 	return &SchemaGenerator{
 		mapper: mapper.NewMapper(),
+		naming: ConventionalNaming{},
 	}
 }
 
+// SchemaMode selects the statements GenerateWithOptions writes.
+type SchemaMode int
+
+const (
+	// ModeCreate creates the schema. It is the default.
+	ModeCreate SchemaMode = iota
+	// ModeDrop drops the tables and views, dependents first.
+	ModeDrop
+	// ModeReset drops the tables and views and creates them again, for
+	// resetting a local development database.
+	ModeReset
+)
+
+// SchemaOptions control the DDL GenerateWithOptions writes.
+type SchemaOptions struct {
+	Mode SchemaMode
+	// IfNotExists makes the CREATE statements safe to re-run: tables,
+	// partitions, indexes and materialized views get IF NOT EXISTS, views
+	// are created with OR REPLACE and deferred foreign keys are dropped
+	// before they are added.
+	IfNotExists bool
+}
+
+// SetNamingStrategy replaces the strategy naming the generated constraints.
+func (g *SchemaGenerator) SetNamingStrategy(naming NamingStrategy) {
+	g.naming = naming
+}
+
+// Generate creates the schema of entities with the default options.
 func (g *SchemaGenerator) Generate(ctx context.Context, entities []*entity.Entity) (string, error) {
+	return g.GenerateWithOptions(ctx, entities, SchemaOptions{})
+}
+
+// GenerateWithOptions writes the DDL selected by opts for entities.
+func (g *SchemaGenerator) GenerateWithOptions(ctx context.Context, entities []*entity.Entity, opts SchemaOptions) (string, error) {
+	switch opts.Mode {
+	case ModeDrop:
+		return g.generateDrop(entities), nil
+	case ModeReset:
+		return g.generateDrop(entities) + g.generateCreate(entities, opts), nil
+	}
+	return g.generateCreate(entities, opts), nil
+}
+
+// generateCreate creates the extensions, schemas, tables and views entities
+// need, in dependency order.
+func (g *SchemaGenerator) generateCreate(entities []*entity.Entity, opts SchemaOptions) string {
 	var sb strings.Builder
 
-	for _, ent := range entities {
+	for _, ext := range g.requiredExtensions(entities) {
+		sb.WriteString(fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s;\n", pq.QuoteIdentifier(ext)))
+	}
+	for _, schema := range g.schemas(entities) {
+		sb.WriteString(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;\n", pq.QuoteIdentifier(schema)))
+	}
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+
+	tables, deferred := g.sortTables(entities)
+	for _, ent := range tables {
 		tableName := g.getTableName(ent)
-		sb.WriteString(g.generateTable(ent, tableName))
-		sb.WriteString(g.generateIndexes(ent, tableName))
+		sb.WriteString(g.generateTable(ent, tableName, deferred[ent], opts))
+		sb.WriteString(g.generatePartitions(ent, tableName, opts))
+		sb.WriteString(g.generateIndexes(ent, tableName, opts))
+		sb.WriteString(g.generateComments(ent, tableName))
 	}
+	sb.WriteString(g.generateDeferredForeignKeys(tables, deferred, opts))
 
-	return sb.String(), nil
+	// Views come after the tables their queries read from
+	for _, ent := range entities {
+		if !ent.View {
+			continue
+		}
+		viewName := g.getTableName(ent)
+		sb.WriteString(g.generateView(ent, viewName, opts))
+		if ent.Materialized {
+			sb.WriteString(g.generateIndexes(ent, viewName, opts))
+		}
+		sb.WriteString(g.generateComments(ent, viewName))
+	}
+
+	return sb.String()
+}
+
+// ifNotExists returns "IF NOT EXISTS " when opts ask for re-runnable DDL.
+func ifNotExists(opts SchemaOptions) string {
+	if opts.IfNotExists {
+		return "IF NOT EXISTS "
+	}
+	return ""
 }
 
-func (g *SchemaGenerator) generateTable(ent *entity.Entity, tableName string) string {
+// generateTable creates a table with its constraints. Foreign keys to the
+// tables in deferred are left out; they are added afterwards by
+// generateDeferredForeignKeys.
+func (g *SchemaGenerator) generateTable(ent *entity.Entity, tableName string, deferred map[string]bool, opts SchemaOptions) string {
 	var sb strings.Builder
 
-	sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", tableName))
+	if ent.Unlogged {
+		sb.WriteString(fmt.Sprintf("CREATE UNLOGGED TABLE %s%s (\n", ifNotExists(opts), tableName))
+	} else {
+		sb.WriteString(fmt.Sprintf("CREATE TABLE %s%s (\n", ifNotExists(opts), tableName))
+	}
 
 	fields := ent.GetGenerateableFields()
-	var columnDefs []string
+	names := g.newConstraintNamer(ent)
+	var defs []string
 	for _, field := range fields {
-		colDef := g.generateColumn(ent, field)
+		colDef := g.generateColumn(ent, field, deferred, names)
 		if colDef != "" {
-			columnDefs = append(columnDefs, colDef)
-		}
-	}
-
-	// Check if we have composite FKs
-	hasCompositeFK := g.hasCompositeForeignKey(fields)
-
-	// Write column definitions
-	for i, col := range columnDefs {
-		sb.WriteString("    ")
-		sb.WriteString(col)
-		if i < len(columnDefs)-1 || ent.HasCompositePrimaryKey() || ent.HasCompositeUniqueConstraints() || hasCompositeFK {
-			sb.WriteString(",\n")
-		} else {
-			sb.WriteString("\n")
+			defs = append(defs, colDef)
 		}
 	}
 
@@ -67,119 +145,187 @@ func (g *SchemaGenerator) generateTable(ent *entity.Entity, tableName string) st
 		var pkColumns []string
 		for _, field := range pkFields {
 			if field.ShouldGenerate() {
-				pkColumns = append(pkColumns, pq.QuoteIdentifier(util.ToSnakeCase(field.Name)))
+				pkColumns = append(pkColumns, pq.QuoteIdentifier(field.ColumnName()))
 			}
 		}
 		if len(pkColumns) > 1 {
-			sb.WriteString(fmt.Sprintf("    PRIMARY KEY (%s)", strings.Join(pkColumns, ", ")))
-			sb.WriteString("\n")
+			defs = append(defs, constraintPrefix(names.primaryKey())+fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(pkColumns, ", ")))
 		}
 	}
 
 	// Add composite UNIQUE constraints
 	uniqueConstraints := ent.GetUniqueConstraints()
-	var constraintNames []string
-	for constraintName, fields := range uniqueConstraints {
-		if len(fields) > 1 {
-			constraintNames = append(constraintNames, constraintName)
-		}
-	}
+	constraintNames := groupNames(ent.Fields, uniqueConstraints, func(field entity.Field) string {
+		return field.IndexGroup
+	})
 
-	for i, constraintName := range constraintNames {
+	for _, constraintName := range constraintNames {
 		fields := uniqueConstraints[constraintName]
-		var uniqueColumns []string
+		if len(fields) < 2 {
+			continue
+		}
+		var columns, uniqueColumns []string
 		for _, field := range fields {
 			if field.ShouldGenerate() {
-				uniqueColumns = append(uniqueColumns, pq.QuoteIdentifier(util.ToSnakeCase(field.Name)))
+				columns = append(columns, field.ColumnName())
+				uniqueColumns = append(uniqueColumns, pq.QuoteIdentifier(field.ColumnName()))
 			}
 		}
 		if len(uniqueColumns) > 1 {
-			sb.WriteString("    UNIQUE")
-			if strings.HasPrefix(constraintName, "uq_") {
-				sb.WriteString(fmt.Sprintf(" %s", pq.QuoteIdentifier(constraintName)))
-			}
-			sb.WriteString(fmt.Sprintf(" (%s)", strings.Join(uniqueColumns, ", ")))
-			if i < len(constraintNames)-1 || ent.HasCompositePrimaryKey() || hasCompositeFK {
-				sb.WriteString(",\n")
-			} else {
-				sb.WriteString("\n")
+			// uq_ groups keep the name they were given
+			name := constraintName
+			if !strings.HasPrefix(constraintName, "uq_") {
+				name = names.unique(columns...)
 			}
+			defs = append(defs, constraintPrefix(name)+fmt.Sprintf("UNIQUE (%s)", strings.Join(uniqueColumns, ", ")))
 		}
 	}
 
 	// Add composite FOREIGN KEY constraints
 	fkGroups := g.groupFieldsByFK(fields)
-	var fkGroupNames []string
-	for groupName := range fkGroups {
-		if len(fkGroups[groupName]) > 1 {
-			fkGroupNames = append(fkGroupNames, groupName)
-		}
-	}
-
-	for i, fkGroupName := range fkGroupNames {
+	for _, fkGroupName := range groupNames(fields, fkGroups, fkGroupKey) {
 		fkFields := fkGroups[fkGroupName]
 		if len(fkFields) < 2 {
 			continue
 		}
 
-		var localColumns []string
-		var refColumns []string
-		var refTable string
-		var onDelete, onUpdate string
+		refTable, reference, ok := g.compositeForeignKey(fkFields)
+		if !ok || deferred[refTable] {
+			continue
+		}
+		defs = append(defs, constraintPrefix(fkGroupName)+"FOREIGN KEY "+reference)
+	}
 
-		for _, field := range fkFields {
-			if !field.ShouldGenerate() || field.FKReference == nil {
-				continue
-			}
-			localColumns = append(localColumns, pq.QuoteIdentifier(util.ToSnakeCase(field.Name)))
-			refColumns = append(refColumns, pq.QuoteIdentifier(field.FKReference.Column))
-			if refTable == "" {
-				refTable = field.FKReference.Table
-			}
-			if field.FKOnDelete != "" {
-				onDelete = field.FKOnDelete
-			}
-			if field.FKOnUpdate != "" {
-				onUpdate = field.FKOnUpdate
-			}
+	// Add table-level CHECK and EXCLUDE constraints from directives
+	for _, check := range ent.Checks {
+		defs = append(defs, constraintPrefix(names.tableCheck(check))+fmt.Sprintf("CHECK (%s)", check))
+	}
+	for _, exclusion := range ent.Exclusions {
+		defs = append(defs, constraintPrefix(names.exclusion(exclusion))+"EXCLUDE "+exclusion)
+	}
+
+	for i, def := range defs {
+		sb.WriteString("    ")
+		sb.WriteString(def)
+		if i < len(defs)-1 {
+			sb.WriteString(",")
 		}
+		sb.WriteString("\n")
+	}
 
-		if len(localColumns) >= 2 {
-			sb.WriteString("    FOREIGN KEY")
-			if fkGroupName != "" {
-				sb.WriteString(fmt.Sprintf(" %s", pq.QuoteIdentifier(fkGroupName)))
-			}
-			sb.WriteString(fmt.Sprintf(" (%s) REFERENCES %s (%s)",
-				strings.Join(localColumns, ", "),
-				pq.QuoteIdentifier(refTable),
-				strings.Join(refColumns, ", ")))
+	sb.WriteString(")")
+	if ent.PartitionBy != nil {
+		var keyColumns []string
+		for _, column := range ent.PartitionBy.Columns {
+			keyColumns = append(keyColumns, pq.QuoteIdentifier(column))
+		}
+		sb.WriteString(fmt.Sprintf(" PARTITION BY %s (%s)", ent.PartitionBy.Method, strings.Join(keyColumns, ", ")))
+	}
+	if len(ent.StorageParams) > 0 {
+		sb.WriteString(fmt.Sprintf(" WITH (%s)", strings.Join(ent.StorageParams, ", ")))
+	}
+	sb.WriteString(";\n\n")
 
-			if onDelete != "" {
-				sb.WriteString(fmt.Sprintf(" ON DELETE %s", g.formatCascadeAction(onDelete)))
-			}
-			if onUpdate != "" {
-				sb.WriteString(fmt.Sprintf(" ON UPDATE %s", g.formatCascadeAction(onUpdate)))
-			}
+	return sb.String()
+}
 
-			if i < len(fkGroupNames)-1 {
-				sb.WriteString(",\n")
-			} else {
-				sb.WriteString("\n")
-			}
+// generateView creates a view, or a materialized view, from its defining
+// query. Re-runnable DDL replaces a plain view, which has no IF NOT EXISTS.
+func (g *SchemaGenerator) generateView(ent *entity.Entity, viewName string, opts SchemaOptions) string {
+	query := strings.TrimSuffix(strings.TrimSpace(ent.ViewSQL), ";")
+	create := "CREATE " + g.objectKind(ent) + " "
+	switch {
+	case opts.IfNotExists && ent.Materialized:
+		create += "IF NOT EXISTS "
+	case opts.IfNotExists:
+		create = "CREATE OR REPLACE VIEW "
+	}
+	return fmt.Sprintf("%s%s AS\n%s;\n\n", create, viewName, query)
+}
+
+// objectKind returns the kind of database object an entity is stored as,
+// as written in DDL: TABLE, VIEW or MATERIALIZED VIEW.
+func (g *SchemaGenerator) objectKind(ent *entity.Entity) string {
+	switch {
+	case ent.Materialized:
+		return "MATERIALIZED VIEW"
+	case ent.View:
+		return "VIEW"
+	}
+	return "TABLE"
+}
+
+// compositeForeignKey renders the columns and reference of a composite
+// foreign key as "(a, b) REFERENCES t (x, y)" with its ON DELETE and
+// ON UPDATE actions. It also returns the referenced table; ok is false when
+// fewer than two of the fields are generated.
+func (g *SchemaGenerator) compositeForeignKey(fkFields []entity.Field) (refTable, reference string, ok bool) {
+	var localColumns []string
+	var refColumns []string
+	var onDelete, onUpdate string
+
+	for _, field := range fkFields {
+		if !field.ShouldGenerate() || field.FKReference == nil {
+			continue
+		}
+		localColumns = append(localColumns, pq.QuoteIdentifier(field.ColumnName()))
+		refColumns = append(refColumns, pq.QuoteIdentifier(field.FKReference.Column))
+		if refTable == "" {
+			refTable = field.FKReference.Table
+		}
+		if field.FKOnDelete != "" {
+			onDelete = field.FKOnDelete
+		}
+		if field.FKOnUpdate != "" {
+			onUpdate = field.FKOnUpdate
 		}
 	}
+	if len(localColumns) < 2 {
+		return "", "", false
+	}
+
+	return refTable, g.foreignKeyReference(localColumns, refTable, refColumns, onDelete, onUpdate), true
+}
 
-	sb.WriteString(");\n\n")
+// foreignKeyReference renders "(cols) REFERENCES table (refCols)" followed by
+// the ON DELETE and ON UPDATE actions. Columns are already quoted.
+func (g *SchemaGenerator) foreignKeyReference(columns []string, refTable string, refColumns []string, onDelete, onUpdate string) string {
+	reference := fmt.Sprintf("(%s) REFERENCES %s (%s)",
+		strings.Join(columns, ", "),
+		entity.QuoteQualifiedName(refTable),
+		strings.Join(refColumns, ", "))
 
+	if onDelete != "" {
+		reference += fmt.Sprintf(" ON DELETE %s", g.formatCascadeAction(onDelete))
+	}
+	if onUpdate != "" {
+		reference += fmt.Sprintf(" ON UPDATE %s", g.formatCascadeAction(onUpdate))
+	}
+	return reference
+}
+
+// generatePartitions creates the child partitions of a partitioned table in
+// the parent's schema.
+func (g *SchemaGenerator) generatePartitions(ent *entity.Entity, tableName string, opts SchemaOptions) string {
+	if ent.PartitionBy == nil || len(ent.Partitions) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, partition := range ent.Partitions {
+		name := entity.QuoteQualifiedName(entity.QualifiedName(ent.Schema, partition.Name))
+		sb.WriteString(fmt.Sprintf("CREATE TABLE %s%s PARTITION OF %s %s;\n", ifNotExists(opts), name, tableName, partition.Bound))
+	}
+	sb.WriteString("\n")
 	return sb.String()
 }
 
-func (g *SchemaGenerator) generateColumn(ent *entity.Entity, field entity.Field) string {
+func (g *SchemaGenerator) generateColumn(ent *entity.Entity, field entity.Field, deferred map[string]bool, names *constraintNamer) string {
 	if !field.ShouldGenerate() {
 		return ""
 	}
 
-	mapping := g.mapper.MapType(field.Type)
+	mapping := g.mapper.MapFieldType(field.Type, field.UnderlyingType)
 	// Don't add PRIMARY KEY here if it's part of a composite PK
 	tags := g.getFieldTags(field)
 	if field.IsPrimary && ent.HasCompositePrimaryKey() {
@@ -202,21 +348,33 @@ func (g *SchemaGenerator) generateColumn(ent *entity.Entity, field entity.Field)
 		}
 		tags = tempTags
 	}
-	columnDef := g.mapper.FormatColumnDefinition(field.Name, mapping, tags)
+	column := field.ColumnName()
+	var pkName, uniqueName, checkName string
+	if g.mapper.HasTag(tags, "pk") {
+		pkName = names.primaryKey()
+	}
+	if g.mapper.HasTag(tags, "unique") {
+		uniqueName = names.unique(column)
+	}
+	if len(mapping.Constraints) > 0 {
+		checkName = names.check(column)
+	}
+	tags = append(tags, mapper.ConstraintTags(pkName, uniqueName, checkName)...)
+	columnDef := g.mapper.FormatColumnDefinition(column, mapping, tags)
+
+	// Add stored generated column expression from field.GeneratedExpr
+	if field.GeneratedExpr != "" {
+		columnDef += fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", field.GeneratedExpr)
+	}
 
 	// Add CHECK constraint from field.CheckExpr
 	if field.CheckExpr != "" {
-		columnDef += fmt.Sprintf(" CHECK (%s)", field.CheckExpr)
+		columnDef += " " + constraintPrefix(names.check(column)) + fmt.Sprintf("CHECK (%s)", field.CheckExpr)
 	}
 
 	// Add enum CHECK constraint from field.EnumValues
 	if len(field.EnumValues) > 0 {
-		enumList := make([]string, len(field.EnumValues))
-		for i, val := range field.EnumValues {
-			enumList[i] = fmt.Sprintf("'%s'", val)
-		}
-		columnName := pq.QuoteIdentifier(util.ToSnakeCase(field.Name))
-		columnDef += fmt.Sprintf(" CHECK (%s IN (%s))", columnName, strings.Join(enumList, ", "))
+		columnDef += " " + constraintPrefix(names.check(column)) + g.enumCheck(field)
 	}
 
 	// Add DEFAULT value from field.DefaultVal
@@ -225,9 +383,9 @@ func (g *SchemaGenerator) generateColumn(ent *entity.Entity, field entity.Field)
 	}
 
 	// Add FOREIGN KEY constraint from field.FKReference (only for single-column FKs)
-	if field.FKReference != nil && field.FKGroup == "" {
-		columnDef += fmt.Sprintf(" REFERENCES %s(%s)",
-			pq.QuoteIdentifier(field.FKReference.Table),
+	if field.FKReference != nil && field.FKGroup == "" && !deferred[field.FKReference.Table] {
+		columnDef += " " + constraintPrefix(names.foreignKey(column)) + fmt.Sprintf("REFERENCES %s(%s)",
+			entity.QuoteQualifiedName(field.FKReference.Table),
 			pq.QuoteIdentifier(field.FKReference.Column))
 
 		// Add ON DELETE clause
@@ -241,7 +399,65 @@ func (g *SchemaGenerator) generateColumn(ent *entity.Entity, field entity.Field)
 		}
 	}
 
-	return pq.QuoteIdentifier(util.ToSnakeCase(field.Name)) + " " + columnDef
+	return pq.QuoteIdentifier(column) + " " + columnDef
+}
+
+// generateComments creates COMMENT ON statements for the documented table
+// and columns
+func (g *SchemaGenerator) generateComments(ent *entity.Entity, tableName string) string {
+	var sb strings.Builder
+
+	if ent.Comment != "" {
+		sb.WriteString(fmt.Sprintf("COMMENT ON %s %s IS %s;\n", g.objectKind(ent), tableName, quoteLiteral(ent.Comment)))
+	}
+	for _, field := range ent.GetGenerateableFields() {
+		if field.Comment == "" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s;\n",
+			tableName, pq.QuoteIdentifier(field.ColumnName()), quoteLiteral(field.Comment)))
+	}
+
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// quoteLiteral quotes s as a string literal. pq.QuoteLiteral prefixes
+// literals containing backslashes with a space and E, the space is dropped.
+func quoteLiteral(s string) string {
+	return strings.TrimPrefix(pq.QuoteLiteral(s), " ")
+}
+
+// enumCheck renders the CHECK constraint limiting field to its enum values.
+// On numeric and boolean columns, values that are numbers or booleans are
+// written bare, so that 0.1 is compared as a number rather than as text.
+func (g *SchemaGenerator) enumCheck(field entity.Field) string {
+	bare := g.mapper.HasBareLiterals(g.columnType(field))
+	values := make([]string, len(field.EnumValues))
+	for i, val := range field.EnumValues {
+		if bare && isBareLiteral(val) {
+			values[i] = val
+		} else {
+			values[i] = quoteLiteral(val)
+		}
+	}
+	return fmt.Sprintf("CHECK (%s IN (%s))", pq.QuoteIdentifier(field.ColumnName()), strings.Join(values, ", "))
+}
+
+// isBareLiteral reports whether s is a decimal number or a boolean that SQL
+// reads without quotes.
+func isBareLiteral(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "false":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return false
+	}
+	// ParseFloat also reads Inf, NaN and hex floats, which SQL does not
+	return strings.Trim(s, "0123456789+-.eE") == ""
 }
 
 // formatCascadeAction formats a cascade action (converts underscores to spaces)
@@ -250,6 +466,57 @@ func (g *SchemaGenerator) formatCascadeAction(action string) string {
 	return strings.ReplaceAll(action, "_", " ")
 }
 
+// requiredExtensions returns the sorted extensions needed by the column
+// types and defaults of entities, e.g. citext for a CITEXT column or
+// uuid-ossp for DEFAULT uuid_generate_v4().
+func (g *SchemaGenerator) requiredExtensions(entities []*entity.Entity) []string {
+	seen := make(map[string]bool)
+	for _, ent := range entities {
+		// A view's column types come from its query
+		if ent.View {
+			continue
+		}
+		for _, field := range ent.GetGenerateableFields() {
+			mapping := g.mapper.MapFieldType(field.Type, field.UnderlyingType)
+			if ext := g.mapper.RequiredExtension(g.mapper.ColumnType(mapping, g.getFieldTags(field))); ext != "" {
+				seen[ext] = true
+			}
+			if strings.Contains(strings.ToLower(field.DefaultVal), "uuid_generate_v") {
+				seen["uuid-ossp"] = true
+			}
+		}
+		// Scalar equality in a GiST exclusion constraint needs the btree
+		// operator classes of btree_gist.
+		for _, exclusion := range ent.Exclusions {
+			lower := strings.ToLower(exclusion)
+			if strings.Contains(lower, "using gist") && strings.Contains(lower, "with =") {
+				seen["btree_gist"] = true
+			}
+		}
+	}
+
+	exts := make([]string, 0, len(seen))
+	for ext := range seen {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// schemas returns the sorted, distinct schemas the entities' tables live in.
+func (g *SchemaGenerator) schemas(entities []*entity.Entity) []string {
+	seen := make(map[string]bool)
+	var schemas []string
+	for _, ent := range entities {
+		if ent.Schema != "" && !seen[ent.Schema] {
+			seen[ent.Schema] = true
+			schemas = append(schemas, ent.Schema)
+		}
+	}
+	sort.Strings(schemas)
+	return schemas
+}
+
 func (g *SchemaGenerator) getFieldTags(field entity.Field) []string {
 	var tags []string
 	if field.IsPrimary {
@@ -261,57 +528,111 @@ func (g *SchemaGenerator) getFieldTags(field entity.Field) []string {
 	if field.IsIgnored {
 		tags = append(tags, "-")
 	}
+	if field.IsNullable {
+		tags = append(tags, "null")
+	}
+	if field.IsNotNull {
+		tags = append(tags, "notnull")
+	}
+	if field.Identity != "" {
+		tags = append(tags, field.Identity)
+	}
+	tags = append(tags, mapper.TypeTags(field.SQLType, field.Size, field.Precision, field.Scale)...)
 	return tags
 }
 
 func (g *SchemaGenerator) getTableName(ent *entity.Entity) string {
-	if ent.TableName != "" {
-		return pq.QuoteIdentifier(ent.TableName)
-	}
-	return pq.QuoteIdentifier(ent.GetTableName())
+	return ent.GetQuotedTableName()
 }
 
 // generateIndexes creates CREATE INDEX statements for fields with index tags
-func (g *SchemaGenerator) generateIndexes(ent *entity.Entity, tableName string) string {
+func (g *SchemaGenerator) generateIndexes(ent *entity.Entity, tableName string, opts SchemaOptions) string {
 	var sb strings.Builder
 
 	// Group fields by index name for composite indexes
 	indexGroups := g.groupFieldsByIndex(ent.Fields)
+	indexNames := groupNames(ent.Fields, indexGroups, func(field entity.Field) string {
+		return field.IndexName
+	})
 
-	for indexName, fields := range indexGroups {
-		if len(fields) == 0 {
-			continue
-		}
+	for _, indexName := range indexNames {
+		sb.WriteString(g.generateIndex(indexName, tableName, indexGroups[indexName], opts))
+	}
 
-		// Build column list
-		columns := make([]string, len(fields))
-		for i, field := range fields {
-			columns[i] = pq.QuoteIdentifier(util.ToSnakeCase(field.Name))
-		}
+	if sb.Len() > 0 {
+		sb.WriteString("\n")
+	}
 
-		// Check if this is a unique index
-		isUnique := fields[0].IsIndexUnique
+	return sb.String()
+}
 
-		if isUnique {
-			sb.WriteString(fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (%s);\n",
-				pq.QuoteIdentifier(indexName),
-				tableName,
-				strings.Join(columns, ", ")))
-		} else {
-			sb.WriteString(fmt.Sprintf("CREATE INDEX %s ON %s (%s);\n",
-				pq.QuoteIdentifier(indexName),
-				tableName,
-				strings.Join(columns, ", ")))
+// generateIndex creates the CREATE INDEX statement for the fields of one
+// index. Index-level options (using:, include:, where:) may be declared on
+// any of the fields.
+func (g *SchemaGenerator) generateIndex(indexName, tableName string, fields []entity.Field, opts SchemaOptions) string {
+	fields = g.sortIndexFields(fields)
+
+	var method, where string
+	var elements, include []string
+	for _, field := range fields {
+		element := pq.QuoteIdentifier(field.ColumnName())
+		if field.IndexExpr != "" {
+			element = "(" + field.IndexExpr + ")"
 		}
-	}
+		if field.IndexDesc {
+			element += " DESC"
+		}
+		if field.IndexNullsLast {
+			element += " NULLS LAST"
+		}
+		elements = append(elements, element)
 
-	if sb.Len() > 0 {
-		sb.WriteString("\n")
+		if method == "" {
+			method = field.IndexMethod
+		}
+		if where == "" {
+			where = field.IndexWhere
+		}
+		for _, column := range field.IndexInclude {
+			include = append(include, pq.QuoteIdentifier(column))
+		}
 	}
 
+	var sb strings.Builder
+	sb.WriteString("CREATE ")
+	// Check if this is a unique index
+	if fields[0].IsIndexUnique {
+		sb.WriteString("UNIQUE ")
+	}
+	sb.WriteString(fmt.Sprintf("INDEX %s%s ON %s", ifNotExists(opts), pq.QuoteIdentifier(indexName), tableName))
+	if method != "" {
+		sb.WriteString(" USING " + method)
+	}
+	sb.WriteString(fmt.Sprintf(" (%s)", strings.Join(elements, ", ")))
+	if len(include) > 0 {
+		sb.WriteString(fmt.Sprintf(" INCLUDE (%s)", strings.Join(include, ", ")))
+	}
+	if where != "" {
+		sb.WriteString(" WHERE " + where)
+	}
+	sb.WriteString(";\n")
 	return sb.String()
 }
 
+// sortIndexFields orders the fields of a composite index: fields with an
+// order: tag come first by position, the rest keep their field order.
+func (g *SchemaGenerator) sortIndexFields(fields []entity.Field) []entity.Field {
+	sorted := append([]entity.Field(nil), fields...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		oi, oj := sorted[i].IndexOrder, sorted[j].IndexOrder
+		if oi == 0 || oj == 0 {
+			return oi != 0 && oj == 0
+		}
+		return oi < oj
+	})
+	return sorted
+}
+
 // groupFieldsByIndex groups fields by their index name
 func (g *SchemaGenerator) groupFieldsByIndex(fields []entity.Field) map[string][]entity.Field {
 	groups := make(map[string][]entity.Field)
@@ -339,6 +660,28 @@ func (g *SchemaGenerator) groupFieldsByFK(fields []entity.Field) map[string][]en
 	return groups
 }
 
+// fkGroupKey returns the composite foreign key group a field belongs to.
+func fkGroupKey(field entity.Field) string {
+	return field.FKGroup
+}
+
+// groupNames returns the names of groups in declaration order: a group
+// comes where its first field is declared in fields. Iterating the groups
+// in this order keeps the generated schema stable from run to run.
+func groupNames(fields []entity.Field, groups map[string][]entity.Field, key func(entity.Field) string) []string {
+	seen := make(map[string]bool, len(groups))
+	names := make([]string, 0, len(groups))
+	for _, field := range fields {
+		name := key(field)
+		if _, ok := groups[name]; !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
 // hasCompositeForeignKey checks if the entity has composite foreign keys
 func (g *SchemaGenerator) hasCompositeForeignKey(fields []entity.Field) bool {
 	fkGroups := g.groupFieldsByFK(fields)
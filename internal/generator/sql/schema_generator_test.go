@@ -195,6 +195,11 @@ func TestSchemaGeneratorGenerateWithEnum(t *testing.T) {
 			Fields: []entity.Field{
 				{Name: "ID", Type: "int64", IsPrimary: true},
 				{Name: "Status", Type: "string", EnumValues: []string{"pending", "processing", "shipped"}},
+				{Name: "Note", Type: "string", EnumValues: []string{"it's", "fine"}},
+				{Name: "Tier", Type: "Tier", UnderlyingType: "int", EnumValues: []string{"0", "1", "2"}},
+				{Name: "Discount", Type: "Ratio", UnderlyingType: "float64", EnumValues: []string{"0", "0.1", "0.25"}},
+				{Name: "Rush", Type: "bool", EnumValues: []string{"false"}},
+				{Name: "Level", Type: "int32", EnumValues: []string{"low", "high"}},
 			},
 		},
 	}
@@ -204,9 +209,24 @@ func TestSchemaGeneratorGenerateWithEnum(t *testing.T) {
 		t.Fatalf("Generate() error = %v", err)
 	}
 
+	// Numbers and booleans are compared as such on numeric columns
+	for _, want := range []string{
+		`CHECK ("tier" IN (0, 1, 2))`,
+		`CHECK ("discount" IN (0, 0.1, 0.25))`,
+		`CHECK ("rush" IN (false))`,
+		`CHECK ("level" IN ('low', 'high'))`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, result)
+		}
+	}
+
 	if !strings.Contains(result, "CHECK (\"status\" IN ('pending', 'processing', 'shipped'))") {
 		t.Error("Generate() result missing CHECK constraint for enum")
 	}
+	if !strings.Contains(result, "CHECK (\"note\" IN ('it''s', 'fine'))") {
+		t.Errorf("Generate() result should escape quotes in enum values, got:\n%s", result)
+	}
 }
 
 func TestSchemaGeneratorGenerateWithCompositeIndex(t *testing.T) {
@@ -373,7 +393,7 @@ func TestSchemaGeneratorGenerateWithCompositeUniqueConstraint(t *testing.T) {
 		t.Fatalf("Generate() error = %v", err)
 	}
 
-	if !strings.Contains(result, `UNIQUE "uq_tenant_role"`) {
+	if !strings.Contains(result, `CONSTRAINT "uq_tenant_role" UNIQUE ("tenant_id", "user_id", "role")`) {
 		t.Errorf("Generate() result missing composite UNIQUE constraint. Got:\n%s", result)
 	}
 	if !strings.Contains(result, `("tenant_id", "user_id", "role")`) {
@@ -419,7 +439,7 @@ func TestSchemaGeneratorGenerateWithCompositeForeignKey(t *testing.T) {
 		t.Fatalf("Generate() error = %v", err)
 	}
 
-	if !strings.Contains(result, `FOREIGN KEY "fk_order_item" ("order_id", "item_id")`) {
+	if !strings.Contains(result, `CONSTRAINT "fk_order_item" FOREIGN KEY ("order_id", "item_id")`) {
 		t.Errorf("Generate() result missing composite FOREIGN KEY. Got:\n%s", result)
 	}
 	if !strings.Contains(result, `REFERENCES "order_item" ("order_id", "item_id")`) {
@@ -572,3 +592,640 @@ func TestSchemaGeneratorHasCompositeForeignKey(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaGeneratorGenerateWithEmbeddedColumns(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Customer",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "ShippingCity", Type: "string", Column: "ship_city", Selector: "Shipping.City", IndexName: "ship_city_idx"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if !strings.Contains(result, `"ship_city" VARCHAR(255)`) {
+		t.Errorf("Generate() missing prefixed column:\n%s", result)
+	}
+	if !strings.Contains(result, `ON "customer" ("ship_city")`) {
+		t.Errorf("Generate() index missing prefixed column:\n%s", result)
+	}
+}
+
+func TestSchemaGeneratorGenerateWithStorageOptions(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name:          "Session",
+			Unlogged:      true,
+			StorageParams: []string{"fillfactor=70"},
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if !strings.Contains(result, `CREATE UNLOGGED TABLE "session"`) {
+		t.Errorf("Generate() missing UNLOGGED:\n%s", result)
+	}
+	if !strings.Contains(result, ") WITH (fillfactor=70);") {
+		t.Errorf("Generate() missing WITH clause:\n%s", result)
+	}
+}
+
+func TestSchemaGeneratorGenerateWithTableConstraints(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name:       "Booking",
+			Checks:     []string{"start_at < end_at"},
+			Exclusions: []string{"USING gist (room_id WITH =, during WITH &&)"},
+			Fields: []entity.Field{
+				{Name: "BookingID", Type: "int64", IsPrimary: true},
+				{Name: "RoomID", Type: "int64", IsPrimary: true},
+				{Name: "StartAt", Type: "time.Time"},
+				{Name: "EndAt", Type: "time.Time"},
+				{Name: "During", Type: "pgtype.Range[time.Time]"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `CREATE EXTENSION IF NOT EXISTS "btree_gist";
+
+CREATE TABLE "booking" (
+    "booking_id" BIGINT NOT NULL,
+    "room_id" BIGINT NOT NULL,
+    "start_at" TIMESTAMP NOT NULL,
+    "end_at" TIMESTAMP NOT NULL,
+    "during" TSTZRANGE NOT NULL,
+    CONSTRAINT "pk_booking" PRIMARY KEY ("booking_id", "room_id"),
+    CONSTRAINT "ck_booking_start_at_end_at" CHECK (start_at < end_at),
+    CONSTRAINT "ex_booking_room_id_during" EXCLUDE USING gist (room_id WITH =, during WITH &&)
+);
+
+`
+	if result != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", result, want)
+	}
+}
+
+func TestSchemaGeneratorGenerateWithSchemas(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name:    "Customer",
+			Schema:  "crm",
+			Comment: "Customer buys things.",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+			},
+		},
+		{
+			Name:   "Invoice",
+			Schema: "billing",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "CustomerID", Type: "int64", IndexName: "idx_invoice_customer", FKReference: &entity.FKReference{Table: "crm.customer", Column: "id"}},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if !strings.HasPrefix(result, "CREATE SCHEMA IF NOT EXISTS \"billing\";\nCREATE SCHEMA IF NOT EXISTS \"crm\";\n\n") {
+		t.Errorf("Generate() missing sorted CREATE SCHEMA statements:\n%s", result)
+	}
+	for _, want := range []string{
+		`CREATE TABLE "crm"."customer" (`,
+		`CREATE TABLE "billing"."invoice" (`,
+		`"customer_id" BIGINT NOT NULL CONSTRAINT "fk_invoice_customer_id" REFERENCES "crm"."customer"("id")`,
+		`ON "billing"."invoice" ("customer_id")`,
+		`COMMENT ON TABLE "crm"."customer" IS`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestSchemaGeneratorGenerateWithPartitions(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name:          "AuditLog",
+			Schema:        "audit",
+			StorageParams: []string{"fillfactor=90"},
+			PartitionBy:   &entity.Partitioning{Method: "RANGE", Columns: []string{"created_at"}},
+			Partitions: []entity.Partition{
+				{Name: "audit_log_2026_01", Bound: "FOR VALUES FROM ('2026-01-01') TO ('2026-02-01')"},
+				{Name: "audit_log_default", Bound: "DEFAULT"},
+			},
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "CreatedAt", Type: "time.Time", IsPrimary: true},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `CREATE SCHEMA IF NOT EXISTS "audit";
+
+CREATE TABLE "audit"."audit_log" (
+    "id" BIGINT NOT NULL,
+    "created_at" TIMESTAMP NOT NULL,
+    CONSTRAINT "pk_audit_log" PRIMARY KEY ("id", "created_at")
+) PARTITION BY RANGE ("created_at") WITH (fillfactor=90);
+
+CREATE TABLE "audit"."audit_log_2026_01" PARTITION OF "audit"."audit_log" FOR VALUES FROM ('2026-01-01') TO ('2026-02-01');
+CREATE TABLE "audit"."audit_log_default" PARTITION OF "audit"."audit_log" DEFAULT;
+
+`
+	if result != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", result, want)
+	}
+}
+
+func TestSchemaGeneratorGenerateWithViews(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name:         "DailySales",
+			View:         true,
+			Materialized: true,
+			ViewSQL:      "SELECT day, sum(total) AS total\nFROM orders\nGROUP BY day;",
+			Fields: []entity.Field{
+				{Name: "Day", Type: "time.Time", IsPrimary: true, IsIndexUnique: true, IndexName: "uq_daily_sales_day"},
+				{Name: "Total", Type: "int64"},
+			},
+		},
+		{
+			Name:    "ActiveUser",
+			View:    true,
+			Comment: "Users seen in the last 30 days.",
+			ViewSQL: "SELECT id FROM users WHERE seen_at > now() - interval '30 days'",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IndexName: "idx_ignored"},
+			},
+		},
+		{
+			Name: "Orders",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `CREATE TABLE "orders" (
+    "id" BIGINT CONSTRAINT "pk_orders" PRIMARY KEY
+);
+
+CREATE MATERIALIZED VIEW "daily_sales" AS
+SELECT day, sum(total) AS total
+FROM orders
+GROUP BY day;
+
+CREATE UNIQUE INDEX "uq_daily_sales_day" ON "daily_sales" ("day");
+
+CREATE VIEW "active_user" AS
+SELECT id FROM users WHERE seen_at > now() - interval '30 days';
+
+COMMENT ON VIEW "active_user" IS 'Users seen in the last 30 days.';
+
+`
+	if result != want {
+		t.Errorf("Generate() =\n%s\nwant\n%s", result, want)
+	}
+}
+
+func TestSchemaGeneratorGenerateWithColumnOverrides(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Membership",
+			Fields: []entity.Field{
+				{Name: "UserID", Type: "int64", IsPrimary: true, Column: "userid"},
+				{Name: "GroupID", Type: "int64", IsPrimary: true, Column: "groupid", FKGroup: "fk_group", FKReference: &entity.FKReference{Table: "groups", Column: "gid"}},
+				{Name: "Status", Type: "string", Column: "state", EnumValues: []string{"on", "off"}, IndexName: "idx_state"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`"userid" BIGINT`,
+		`PRIMARY KEY ("userid", "groupid")`,
+		`"state" VARCHAR(255) NOT NULL CONSTRAINT "ck_membership_state" CHECK ("state" IN ('on', 'off'))`,
+		`ON "membership" ("state")`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestSchemaGeneratorGenerateWithTypeOverrides(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Invoice",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Code", Type: "string", Size: 64, IsUnique: true},
+				{Name: "Total", Type: "float64", Precision: 12, Scale: 2, CheckExpr: "total >= 0"},
+				{Name: "Email", Type: "string", SQLType: "CITEXT"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`"code" VARCHAR(64) NOT NULL CONSTRAINT "uq_invoice_code" UNIQUE`,
+		`"total" NUMERIC(12,2) NOT NULL CONSTRAINT "ck_invoice_total" CHECK (total >= 0)`,
+		`"email" CITEXT NOT NULL`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestSchemaGeneratorGenerateWithNullability(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Profile",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Name", Type: "string"},
+				{Name: "Bio", Type: "*string"},
+				{Name: "DeletedAt", Type: "sql.NullTime"},
+				{Name: "Score", Type: "sql.Null[int32]"},
+				{Name: "Nickname", Type: "string", IsNullable: true},
+				{Name: "Settings", Type: "json.RawMessage", IsNotNull: true},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`"name" VARCHAR(255) NOT NULL,`,
+		`"bio" VARCHAR(255),`,
+		`"deleted_at" TIMESTAMP,`,
+		`"score" INTEGER,`,
+		`"nickname" VARCHAR(255),`,
+		`"settings" JSONB NOT NULL`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestSchemaGeneratorGenerateWithExtendedTypes(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Device",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "uuid.UUID", IsPrimary: true, DefaultVal: "uuid_generate_v4()"},
+				{Name: "Email", Type: "string", SQLType: "CITEXT"},
+				{Name: "Price", Type: "decimal.Decimal"},
+				{Name: "Addr", Type: "netip.Addr"},
+				{Name: "Timeout", Type: "time.Duration"},
+				{Name: "Tags", Type: "[]string"},
+				{Name: "Scores", Type: "[]int64"},
+				{Name: "Meta", Type: "map[string]any"},
+				{Name: "Retries", Type: "uint16"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	wantPrefix := "CREATE EXTENSION IF NOT EXISTS \"citext\";\nCREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";\n\nCREATE TABLE"
+	if !strings.HasPrefix(result, wantPrefix) {
+		t.Errorf("Generate() does not start with %q:\n%s", wantPrefix, result)
+	}
+
+	for _, want := range []string{
+		`"id" UUID CONSTRAINT "pk_device" PRIMARY KEY DEFAULT uuid_generate_v4()`,
+		`"email" CITEXT NOT NULL`,
+		`"price" NUMERIC NOT NULL`,
+		`"addr" INET NOT NULL`,
+		`"timeout" INTERVAL NOT NULL`,
+		`"tags" TEXT[],`,
+		`"scores" BIGINT[],`,
+		`"meta" JSONB,`,
+		`"retries" SMALLINT NOT NULL CONSTRAINT "ck_device_retries" CHECK ("retries" >= 0)`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestSchemaGeneratorGenerateWithIdentity(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Ticket",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true, Identity: "identity"},
+				{Name: "Number", Type: "int32", Identity: "serial"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`"id" BIGINT GENERATED ALWAYS AS IDENTITY CONSTRAINT "pk_ticket" PRIMARY KEY,`,
+		`"number" SERIAL NOT NULL`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestSchemaGeneratorGenerateWithGeneratedColumn(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Member",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Email", Type: "string"},
+				{Name: "EmailNormalized", Type: "string", GeneratedExpr: "lower(email)", IsUnique: true},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	want := `"email_normalized" VARCHAR(255) NOT NULL CONSTRAINT "uq_member_email_normalized" UNIQUE GENERATED ALWAYS AS (lower(email)) STORED`
+	if !strings.Contains(result, want) {
+		t.Errorf("Generate() missing %q:\n%s", want, result)
+	}
+}
+
+func TestSchemaGeneratorGenerateWithComments(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name:    "Customer",
+			Comment: "Customer is somebody who buys things.",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true, Comment: "The customer's number."},
+				{Name: "Name", Type: "string", Column: "display_name", Comment: "Shown in\nreceipts"},
+				{Name: "Path", Type: "string", Comment: `Windows path like C:\data`},
+				{Name: "Secret", Type: "string", IsIgnored: true, Comment: "never stored"},
+				{Name: "Note", Type: "string"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`COMMENT ON TABLE "customer" IS 'Customer is somebody who buys things.';`,
+		`COMMENT ON COLUMN "customer"."id" IS 'The customer''s number.';`,
+		"COMMENT ON COLUMN \"customer\".\"display_name\" IS 'Shown in\nreceipts';",
+		`COMMENT ON COLUMN "customer"."path" IS E'Windows path like C:\\data';`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, result)
+		}
+	}
+	for _, unwanted := range []string{`"secret" IS`, `"note" IS`} {
+		if strings.Contains(result, unwanted) {
+			t.Errorf("Generate() contains %q:\n%s", unwanted, result)
+		}
+	}
+}
+
+func TestSchemaGeneratorGenerateWithIndexOptions(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Article",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Email", Type: "string", IndexName: "uq_email", IsIndexUnique: true, IndexExpr: "lower(email)", IndexWhere: "deleted_at IS NULL"},
+				{Name: "Tags", Type: "[]string", IndexName: "idx_tags", IndexMethod: "gin"},
+				{Name: "Title", Type: "string", IndexName: "idx_feed", IndexOrder: 2, IndexInclude: []string{"title"}},
+				{Name: "PublishedAt", Type: "*time.Time", IndexName: "idx_feed", IndexOrder: 1, IndexDesc: true, IndexNullsLast: true},
+				{Name: "Author", Type: "string", IndexName: "idx_feed"},
+			},
+		},
+	}
+
+	result, err := gen.Generate(context.Background(), entities)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`CREATE UNIQUE INDEX "uq_email" ON "article" ((lower(email))) WHERE deleted_at IS NULL;`,
+		`CREATE INDEX "idx_tags" ON "article" USING gin ("tags");`,
+		`CREATE INDEX "idx_feed" ON "article" ("published_at" DESC NULLS LAST, "title", "author") INCLUDE ("title");`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("Generate() missing %q:\n%s", want, result)
+		}
+	}
+}
+
+func TestSchemaGeneratorGenerateInDeclarationOrder(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name: "Shelf",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Code", Type: "string", IsPrimary: true},
+			},
+		},
+		{
+			Name: "Slot",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Zone", Type: "string", IsUnique: true, IndexGroup: "uq_zone_bin", IndexName: "idx_zone"},
+				{Name: "Bin", Type: "string", IsUnique: true, IndexGroup: "uq_zone_bin", IndexName: "idx_bin"},
+				{Name: "ShelfID", Type: "int64", FKGroup: "fk_shelf", FKReference: &entity.FKReference{Table: "shelf", Column: "id"}},
+				{Name: "ShelfCode", Type: "string", FKGroup: "fk_shelf", FKReference: &entity.FKReference{Table: "shelf", Column: "code"}},
+				{Name: "AltShelfID", Type: "int64", FKGroup: "fk_alt_shelf", FKReference: &entity.FKReference{Table: "shelf", Column: "id"}},
+				{Name: "AltShelfCode", Type: "string", FKGroup: "fk_alt_shelf", FKReference: &entity.FKReference{Table: "shelf", Column: "code"}},
+				{Name: "Row", Type: "int", IsUnique: true, IndexGroup: "uq_row_col", IndexName: "idx_row"},
+				{Name: "Col", Type: "int", IsUnique: true, IndexGroup: "uq_row_col", IndexName: "idx_alpha"},
+			},
+		},
+	}
+
+	want := `CREATE TABLE "shelf" (
+    "id" BIGINT NOT NULL,
+    "code" VARCHAR(255) NOT NULL,
+    CONSTRAINT "pk_shelf" PRIMARY KEY ("id", "code")
+);
+
+CREATE TABLE "slot" (
+    "id" BIGINT CONSTRAINT "pk_slot" PRIMARY KEY,
+    "zone" VARCHAR(255) NOT NULL,
+    "bin" VARCHAR(255) NOT NULL,
+    "shelf_id" BIGINT NOT NULL,
+    "shelf_code" VARCHAR(255) NOT NULL,
+    "alt_shelf_id" BIGINT NOT NULL,
+    "alt_shelf_code" VARCHAR(255) NOT NULL,
+    "row" INTEGER NOT NULL,
+    "col" INTEGER NOT NULL,
+    CONSTRAINT "uq_zone_bin" UNIQUE ("zone", "bin"),
+    CONSTRAINT "uq_row_col" UNIQUE ("row", "col"),
+    CONSTRAINT "fk_shelf" FOREIGN KEY ("shelf_id", "shelf_code") REFERENCES "shelf" ("id", "code"),
+    CONSTRAINT "fk_alt_shelf" FOREIGN KEY ("alt_shelf_id", "alt_shelf_code") REFERENCES "shelf" ("id", "code")
+);
+
+CREATE INDEX "idx_zone" ON "slot" ("zone");
+CREATE INDEX "idx_bin" ON "slot" ("bin");
+CREATE INDEX "idx_row" ON "slot" ("row");
+CREATE INDEX "idx_alpha" ON "slot" ("col");
+
+`
+
+	// Map iteration order differs between runs; one lucky run proves nothing.
+	for i := 0; i < 20; i++ {
+		result, err := gen.Generate(context.Background(), entities)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		if result != want {
+			t.Fatalf("Generate() run %d =\n%s\nwant\n%s", i, result, want)
+		}
+	}
+}
+
+func TestSchemaGeneratorGenerateIfNotExists(t *testing.T) {
+	gen := NewSchemaGenerator()
+
+	entities := []*entity.Entity{
+		{
+			Name:        "Event",
+			PartitionBy: &entity.Partitioning{Method: "LIST", Columns: []string{"region"}},
+			Partitions:  []entity.Partition{{Name: "event_eu", Bound: "FOR VALUES IN ('eu')"}},
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "Region", Type: "string", IsPrimary: true},
+				{Name: "NodeID", Type: "int64", FKReference: &entity.FKReference{Table: "node", Column: "id"}},
+			},
+		},
+		{
+			Name: "Node",
+			Fields: []entity.Field{
+				{Name: "ID", Type: "int64", IsPrimary: true},
+				{Name: "LastEventID", Type: "*int64", IndexName: "idx_node_last_event", FKReference: &entity.FKReference{Table: "event", Column: "id"}},
+			},
+		},
+		{Name: "EventCount", View: true, Materialized: true, ViewSQL: "SELECT count(*) AS total FROM event"},
+		{Name: "EuEvent", View: true, ViewSQL: "SELECT * FROM event_eu"},
+	}
+
+	result, err := gen.GenerateWithOptions(context.Background(), entities, SchemaOptions{IfNotExists: true})
+	if err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+
+	want := `CREATE TABLE IF NOT EXISTS "node" (
+    "id" BIGINT CONSTRAINT "pk_node" PRIMARY KEY,
+    "last_event_id" BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS "idx_node_last_event" ON "node" ("last_event_id");
+
+CREATE TABLE IF NOT EXISTS "event" (
+    "id" BIGINT NOT NULL,
+    "region" VARCHAR(255) NOT NULL,
+    "node_id" BIGINT NOT NULL CONSTRAINT "fk_event_node_id" REFERENCES "node"("id"),
+    CONSTRAINT "pk_event" PRIMARY KEY ("id", "region")
+) PARTITION BY LIST ("region");
+
+CREATE TABLE IF NOT EXISTS "event_eu" PARTITION OF "event" FOR VALUES IN ('eu');
+
+ALTER TABLE "node" DROP CONSTRAINT IF EXISTS "fk_node_last_event_id", ADD CONSTRAINT "fk_node_last_event_id" FOREIGN KEY ("last_event_id") REFERENCES "event" ("id");
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS "event_count" AS
+SELECT count(*) AS total FROM event;
+
+CREATE OR REPLACE VIEW "eu_event" AS
+SELECT * FROM event_eu;
+
+`
+	if result != want {
+		t.Errorf("GenerateWithOptions() =\n%s\nwant\n%s", result, want)
+	}
+}
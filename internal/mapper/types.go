@@ -1,6 +1,9 @@
 package mapper
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+)
 
 type TypeMapping struct {
 	GoType       string
@@ -15,36 +18,174 @@ var typeMappings = map[string]TypeMapping{
 	"int16":           {GoType: "int16", PostgresType: "SMALLINT", IsNotNull: true},
 	"int32":           {GoType: "int32", PostgresType: "INTEGER", IsNotNull: true},
 	"int64":           {GoType: "int64", PostgresType: "BIGINT", IsNotNull: true},
-	"uint":            {GoType: "uint", PostgresType: "BIGINT", Constraints: []string{"CHECK (\" || column_name || \" >= 0)"}, IsNotNull: true},
-	"uint8":           {GoType: "uint8", PostgresType: "SMALLINT", Constraints: []string{"CHECK (\" || column_name || \" >= 0)"}, IsNotNull: true},
-	"uint16":          {GoType: "uint16", PostgresType: "SMALLINT", Constraints: []string{"CHECK (\" || column_name || \" >= 0)"}, IsNotNull: true},
-	"uint32":          {GoType: "uint32", PostgresType: "INTEGER", Constraints: []string{"CHECK (\" || column_name || \" >= 0)"}, IsNotNull: true},
-	"uint64":          {GoType: "uint64", PostgresType: "BIGINT", Constraints: []string{"CHECK (\" || column_name || \" >= 0)"}, IsNotNull: true},
+	"uint":            {GoType: "uint", PostgresType: "BIGINT", Constraints: []string{"CHECK ({column} >= 0)"}, IsNotNull: true},
+	"uint8":           {GoType: "uint8", PostgresType: "SMALLINT", Constraints: []string{"CHECK ({column} >= 0)"}, IsNotNull: true},
+	"uint16":          {GoType: "uint16", PostgresType: "SMALLINT", Constraints: []string{"CHECK ({column} >= 0)"}, IsNotNull: true},
+	"uint32":          {GoType: "uint32", PostgresType: "INTEGER", Constraints: []string{"CHECK ({column} >= 0)"}, IsNotNull: true},
+	"uint64":          {GoType: "uint64", PostgresType: "BIGINT", Constraints: []string{"CHECK ({column} >= 0)"}, IsNotNull: true},
 	"float32":         {GoType: "float32", PostgresType: "REAL", IsNotNull: true},
 	"float64":         {GoType: "float64", PostgresType: "DOUBLE PRECISION", IsNotNull: true},
-	"string":          {GoType: "string", PostgresType: "VARCHAR(255)", IsNotNull: false},
+	"string":          {GoType: "string", PostgresType: "VARCHAR(255)", IsNotNull: true},
 	"bool":            {GoType: "bool", PostgresType: "BOOLEAN", IsNotNull: true},
 	"time.Time":       {GoType: "time.Time", PostgresType: "TIMESTAMP", IsNotNull: true},
 	"json.RawMessage": {GoType: "json.RawMessage", PostgresType: "JSONB", IsNotNull: false},
 	"[]byte":          {GoType: "[]byte", PostgresType: "BYTEA", IsNotNull: false},
+	"sql.NullString":  {GoType: "sql.NullString", PostgresType: "VARCHAR(255)", IsNotNull: false},
+	"sql.NullInt64":   {GoType: "sql.NullInt64", PostgresType: "BIGINT", IsNotNull: false},
+	"sql.NullInt32":   {GoType: "sql.NullInt32", PostgresType: "INTEGER", IsNotNull: false},
+	"sql.NullInt16":   {GoType: "sql.NullInt16", PostgresType: "SMALLINT", IsNotNull: false},
+	"sql.NullByte":    {GoType: "sql.NullByte", PostgresType: "SMALLINT", IsNotNull: false},
+	"sql.NullFloat64": {GoType: "sql.NullFloat64", PostgresType: "DOUBLE PRECISION", IsNotNull: false},
+	"sql.NullBool":    {GoType: "sql.NullBool", PostgresType: "BOOLEAN", IsNotNull: false},
+	"sql.NullTime":    {GoType: "sql.NullTime", PostgresType: "TIMESTAMP", IsNotNull: false},
+	"uuid.UUID":       {GoType: "uuid.UUID", PostgresType: "UUID", IsNotNull: true},
+	"decimal.Decimal": {GoType: "decimal.Decimal", PostgresType: "NUMERIC", IsNotNull: true},
+	"netip.Addr":      {GoType: "netip.Addr", PostgresType: "INET", IsNotNull: true},
+	"netip.Prefix":    {GoType: "netip.Prefix", PostgresType: "CIDR", IsNotNull: true},
+	"time.Duration":   {GoType: "time.Duration", PostgresType: "INTERVAL", IsNotNull: true},
+	"[]string":        {GoType: "[]string", PostgresType: "TEXT[]", IsNotNull: false},
+	"[]int64":         {GoType: "[]int64", PostgresType: "BIGINT[]", IsNotNull: false},
+	"map[string]any":  {GoType: "map[string]any", PostgresType: "JSONB", IsNotNull: false},
+
+	"map[string]interface{}": {GoType: "map[string]interface{}", PostgresType: "JSONB", IsNotNull: false},
+
+	// Range types of pgx (pgtype.Range[T]), usable in EXCLUDE constraints
+	"pgtype.Range[time.Time]":          {GoType: "pgtype.Range[time.Time]", PostgresType: "TSTZRANGE", IsNotNull: true},
+	"pgtype.Range[pgtype.Timestamptz]": {GoType: "pgtype.Range[pgtype.Timestamptz]", PostgresType: "TSTZRANGE", IsNotNull: true},
+	"pgtype.Range[pgtype.Timestamp]":   {GoType: "pgtype.Range[pgtype.Timestamp]", PostgresType: "TSRANGE", IsNotNull: true},
+	"pgtype.Range[pgtype.Date]":        {GoType: "pgtype.Range[pgtype.Date]", PostgresType: "DATERANGE", IsNotNull: true},
+	"pgtype.Range[int32]":              {GoType: "pgtype.Range[int32]", PostgresType: "INT4RANGE", IsNotNull: true},
+	"pgtype.Range[pgtype.Int4]":        {GoType: "pgtype.Range[pgtype.Int4]", PostgresType: "INT4RANGE", IsNotNull: true},
+	"pgtype.Range[int64]":              {GoType: "pgtype.Range[int64]", PostgresType: "INT8RANGE", IsNotNull: true},
+	"pgtype.Range[pgtype.Int8]":        {GoType: "pgtype.Range[pgtype.Int8]", PostgresType: "INT8RANGE", IsNotNull: true},
+	"pgtype.Range[pgtype.Numeric]":     {GoType: "pgtype.Range[pgtype.Numeric]", PostgresType: "NUMRANGE", IsNotNull: true},
+}
+
+// extensions lists the PostgreSQL extensions providing column types that
+// are not built in.
+var extensions = map[string]string{
+	"CITEXT":    "citext",
+	"HSTORE":    "hstore",
+	"LTREE":     "ltree",
+	"GEOMETRY":  "postgis",
+	"GEOGRAPHY": "postgis",
+}
+
+// numericTypes are the number column types. A precision: tag turns them
+// into NUMERIC(p,s).
+var numericTypes = map[string]bool{
+	"SMALLINT":         true,
+	"INTEGER":          true,
+	"BIGINT":           true,
+	"REAL":             true,
+	"DOUBLE PRECISION": true,
+	"NUMERIC":          true,
+	"DECIMAL":          true,
 }
 
+// characterTypes are the column types a size: tag sets the length of.
+var characterTypes = map[string]bool{"VARCHAR": true, "TEXT": true, "CHAR": true}
+
+// timeTypes are the column types a precision: tag sets the fractional
+// seconds precision of.
+var timeTypes = map[string]bool{"TIMESTAMP": true, "TIMESTAMPTZ": true, "TIME": true, "TIMETZ": true, "INTERVAL": true}
+
 type Mapper struct{}
 
 func NewMapper() *Mapper {
 	return &Mapper{}
 }
 
+// MapType maps a Go type to its column type. Pointers and sql.Null[T] map
+// like their element type but are nullable; a slice []T without a mapping
+// of its own becomes an array of T's column type.
 func (m *Mapper) MapType(goType string) TypeMapping {
-	baseType := m.getBaseType(goType)
-	if mapping, ok := typeMappings[baseType]; ok {
+	if inner, ok := nullTypeArg(goType); ok {
+		mapping := m.MapType(inner)
+		mapping.GoType = goType
+		mapping.IsNotNull = false
 		return mapping
 	}
-	return TypeMapping{GoType: goType, PostgresType: "TEXT", IsNotNull: false}
+
+	if elem, ok := sliceElem(goType); ok {
+		if elem == "byte" || elem == "uint8" {
+			mapping := typeMappings["[]byte"]
+			mapping.GoType = goType
+			return mapping
+		}
+		return TypeMapping{GoType: goType, PostgresType: m.MapType(elem).PostgresType + "[]", IsNotNull: false}
+	}
+
+	baseType := m.getBaseType(goType)
+	mapping, ok := typeMappings[baseType]
+	if !ok {
+		return TypeMapping{GoType: goType, PostgresType: "TEXT", IsNotNull: false}
+	}
+	if strings.HasPrefix(goType, "*") {
+		mapping.IsNotNull = false
+	}
+	return mapping
+}
+
+// nullTypeArg returns T for the generic sql.Null[T].
+func nullTypeArg(goType string) (string, bool) {
+	inner, ok := strings.CutPrefix(goType, "sql.Null[")
+	if !ok || !strings.HasSuffix(inner, "]") {
+		return "", false
+	}
+	return strings.TrimSuffix(inner, "]"), true
+}
+
+// sliceElem returns T for a slice []T, or a pointer to one, that has no
+// mapping of its own.
+func sliceElem(goType string) (string, bool) {
+	goType = strings.TrimPrefix(goType, "*")
+	if _, ok := typeMappings[goType]; ok {
+		return "", false
+	}
+	return strings.CutPrefix(goType, "[]")
+}
+
+// Nullable reports whether a column accepts NULL. db:"null" and db:"notnull"
+// override the nullability of the Go type; primary keys never accept NULL.
+func (m *Mapper) Nullable(mapping TypeMapping, tags []string) bool {
+	switch {
+	case m.HasTag(tags, "pk"), m.HasTag(tags, "notnull"):
+		return false
+	case m.HasTag(tags, "null"):
+		return true
+	}
+	return !mapping.IsNotNull
+}
+
+// CanHoldNull reports whether database/sql can scan NULL into a value of
+// goType: pointers, the sql.Null types, []byte and interfaces.
+func (m *Mapper) CanHoldNull(goType string) bool {
+	return strings.HasPrefix(goType, "*") ||
+		strings.HasPrefix(goType, "sql.Null") ||
+		goType == "[]byte" || goType == "any" || goType == "interface{}"
+}
+
+// MapFieldType maps a declared Go type, falling back to its resolved
+// underlying type when the declared type has no mapping of its own.
+// A named type like `type Email string` thus maps like string.
+func (m *Mapper) MapFieldType(goType, underlyingType string) TypeMapping {
+	if _, ok := typeMappings[m.getBaseType(goType)]; ok || underlyingType == "" {
+		return m.MapType(goType)
+	}
+	mapping := m.MapType(underlyingType)
+	mapping.GoType = goType
+	return mapping
 }
 
 func (m *Mapper) getBaseType(goType string) string {
 	goType = strings.TrimPrefix(goType, "*")
+
+	// Slice and map types with a mapping of their own (e.g. []byte,
+	// []string, map[string]any) keep their full name.
+	if _, ok := typeMappings[goType]; ok {
+		return goType
+	}
 	goType = strings.TrimPrefix(goType, "[]")
 
 	if idx := strings.Index(goType, "["); idx != -1 {
@@ -69,7 +210,7 @@ func (m *Mapper) getBaseType(goType string) string {
 }
 
 func (m *Mapper) FormatColumnDefinition(fieldName string, mapping TypeMapping, tags []string) string {
-	def := mapping.PostgresType
+	def := m.ColumnType(mapping, tags)
 
 	constraints := m.parseConstraints(tags)
 	constraints = append(constraints, mapping.Constraints...)
@@ -78,27 +219,190 @@ func (m *Mapper) FormatColumnDefinition(fieldName string, mapping TypeMapping, t
 		return ""
 	}
 
+	if m.HasTag(tags, "identity") {
+		def += " GENERATED ALWAYS AS IDENTITY"
+	}
+
 	if m.HasTag(tags, "pk") {
-		def += " PRIMARY KEY"
+		def += " " + m.namedConstraint(tags, "pk_name", "PRIMARY KEY")
 	}
 
-	if mapping.IsNotNull && !m.HasTag(tags, "pk") {
+	if !m.HasTag(tags, "pk") && !m.Nullable(mapping, tags) {
 		def += " NOT NULL"
 	}
 
 	if m.HasTag(tags, "unique") {
-		def += " UNIQUE"
+		def += " " + m.namedConstraint(tags, "unique_name", "UNIQUE")
 	}
 
+	checkNamed := false
 	for _, constraint := range constraints {
-		if constraint != "" {
-			def += " " + constraint
+		if constraint == "" {
+			continue
+		}
+		constraint = strings.ReplaceAll(constraint, "{column}", quoteIdentifier(fieldName))
+		if !checkNamed && strings.HasPrefix(constraint, "CHECK ") {
+			constraint = m.namedConstraint(tags, "check_name", constraint)
+			checkNamed = true
+		}
+		def += " " + constraint
+	}
+
+	return def
+}
+
+// ColumnType returns the column type of mapping after applying the serial,
+// bigserial, type:, size:, precision: and scale: tags. serial and bigserial
+// replace the type, then an explicit type: wins; size: sets the length of a
+// character type; precision: and scale: turn the column into NUMERIC(p,s),
+// or set the fractional seconds precision of a time type.
+func (m *Mapper) ColumnType(mapping TypeMapping, tags []string) string {
+	switch {
+	case m.HasTag(tags, "serial"):
+		return "SERIAL"
+	case m.HasTag(tags, "bigserial"):
+		return "BIGSERIAL"
+	}
+
+	if sqlType := m.tagValue(tags, "type"); sqlType != "" {
+		return sqlType
+	}
+
+	// size: and precision: apply to the elements of an array
+	def, array := strings.CutSuffix(mapping.PostgresType, "[]")
+	base := modifiedType(def)
+
+	if size := m.tagValue(tags, "size"); size != "" && characterTypes[base] {
+		if base == "CHAR" {
+			def = "CHAR(" + size + ")"
+		} else {
+			def = "VARCHAR(" + size + ")"
 		}
 	}
 
+	if precision := m.tagValue(tags, "precision"); precision != "" {
+		switch {
+		case timeTypes[base]:
+			def = base + "(" + precision + ")"
+		case numericTypes[base]:
+			def = "NUMERIC(" + precision
+			if scale := m.tagValue(tags, "scale"); scale != "" {
+				def += "," + scale
+			}
+			def += ")"
+		}
+	}
+
+	if array {
+		def += "[]"
+	}
 	return def
 }
 
+// HasBareLiterals reports whether values of sqlType, such as INTEGER or
+// NUMERIC(8,2), are numbers or booleans written without quotes.
+func (m *Mapper) HasBareLiterals(sqlType string) bool {
+	base, _, _ := strings.Cut(sqlType, "(")
+	base = strings.ToUpper(strings.TrimSpace(base))
+	return numericTypes[base] || base == "BOOLEAN"
+}
+
+// AcceptsSize reports whether a size: tag applies to mapping's type.
+func (m *Mapper) AcceptsSize(mapping TypeMapping) bool {
+	return characterTypes[modifiedType(mapping.PostgresType)]
+}
+
+// AcceptsPrecision reports whether a precision: tag applies to mapping's
+// type: a number or a time.
+func (m *Mapper) AcceptsPrecision(mapping TypeMapping) bool {
+	base := modifiedType(mapping.PostgresType)
+	return numericTypes[base] || timeTypes[base]
+}
+
+// AcceptsScale reports whether a scale: tag applies to mapping's type.
+func (m *Mapper) AcceptsScale(mapping TypeMapping) bool {
+	return numericTypes[modifiedType(mapping.PostgresType)]
+}
+
+// modifiedType returns the type size: and precision: act on: the element
+// type of an array, without its modifiers.
+func modifiedType(sqlType string) string {
+	def, _ := strings.CutSuffix(sqlType, "[]")
+	base, _, _ := strings.Cut(def, "(")
+	return base
+}
+
+// RequiredExtension returns the extension that provides sqlType, or "" for
+// built-in types.
+func (m *Mapper) RequiredExtension(sqlType string) string {
+	base := strings.ToUpper(strings.TrimSpace(sqlType))
+	base = strings.TrimSuffix(base, "[]")
+	if idx := strings.Index(base, "("); idx != -1 {
+		base = base[:idx]
+	}
+	return extensions[base]
+}
+
+// quoteIdentifier quotes name as a PostgreSQL identifier.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// namedConstraint prefixes constraint with "CONSTRAINT name" when the tags
+// carry a name under key, as rendered by ConstraintTags.
+func (m *Mapper) namedConstraint(tags []string, key, constraint string) string {
+	if name := m.tagValue(tags, key); name != "" {
+		return "CONSTRAINT " + quoteIdentifier(name) + " " + constraint
+	}
+	return constraint
+}
+
+// tagValue returns the value of the key:value tag with the given key.
+func (m *Mapper) tagValue(tags []string, key string) string {
+	for _, tag := range tags {
+		if value, ok := strings.CutPrefix(tag, key+":"); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// TypeTags renders explicit column type settings as the tags understood by
+// ColumnType. Zero values are omitted.
+func TypeTags(sqlType string, size, precision, scale int) []string {
+	var tags []string
+	if sqlType != "" {
+		tags = append(tags, "type:"+sqlType)
+	}
+	if size > 0 {
+		tags = append(tags, "size:"+strconv.Itoa(size))
+	}
+	if precision > 0 {
+		tags = append(tags, "precision:"+strconv.Itoa(precision))
+		if scale > 0 {
+			tags = append(tags, "scale:"+strconv.Itoa(scale))
+		}
+	}
+	return tags
+}
+
+// ConstraintTags renders the names of a column's PRIMARY KEY, UNIQUE and
+// first CHECK constraint as the tags understood by FormatColumnDefinition.
+// Empty names are omitted.
+func ConstraintTags(pk, unique, check string) []string {
+	var tags []string
+	if pk != "" {
+		tags = append(tags, "pk_name:"+pk)
+	}
+	if unique != "" {
+		tags = append(tags, "unique_name:"+unique)
+	}
+	if check != "" {
+		tags = append(tags, "check_name:"+check)
+	}
+	return tags
+}
+
 func (m *Mapper) parseConstraints(tags []string) []string {
 	var constraints []string
 	for _, tag := range tags {
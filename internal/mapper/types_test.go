@@ -1,6 +1,7 @@
 package mapper
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -23,7 +24,7 @@ func TestMapperMapType(t *testing.T) {
 			name:        "string",
 			goType:      "string",
 			wantType:    "VARCHAR(255)",
-			wantNotNull: false,
+			wantNotNull: true,
 		},
 		{
 			name:        "bool",
@@ -53,8 +54,152 @@ func TestMapperMapType(t *testing.T) {
 			name:        "pointer to time.Time",
 			goType:      "*time.Time",
 			wantType:    "TIMESTAMP",
+			wantNotNull: false,
+		},
+		{
+			name:        "pointer to string",
+			goType:      "*string",
+			wantType:    "VARCHAR(255)",
+			wantNotNull: false,
+		},
+		{
+			name:        "sql.NullString",
+			goType:      "sql.NullString",
+			wantType:    "VARCHAR(255)",
+			wantNotNull: false,
+		},
+		{
+			name:        "sql.NullTime",
+			goType:      "sql.NullTime",
+			wantType:    "TIMESTAMP",
+			wantNotNull: false,
+		},
+		{
+			name:        "generic sql.Null",
+			goType:      "sql.Null[int64]",
+			wantType:    "BIGINT",
+			wantNotNull: false,
+		},
+		{
+			name:        "uuid.UUID",
+			goType:      "uuid.UUID",
+			wantType:    "UUID",
+			wantNotNull: true,
+		},
+		{
+			name:        "decimal.Decimal",
+			goType:      "decimal.Decimal",
+			wantType:    "NUMERIC",
+			wantNotNull: true,
+		},
+		{
+			name:        "netip.Addr",
+			goType:      "netip.Addr",
+			wantType:    "INET",
+			wantNotNull: true,
+		},
+		{
+			name:        "time.Duration",
+			goType:      "time.Duration",
+			wantType:    "INTERVAL",
+			wantNotNull: true,
+		},
+		{
+			name:        "string slice",
+			goType:      "[]string",
+			wantType:    "TEXT[]",
+			wantNotNull: false,
+		},
+		{
+			name:        "int64 slice",
+			goType:      "[]int64",
+			wantType:    "BIGINT[]",
+			wantNotNull: false,
+		},
+		{
+			name:        "uuid slice",
+			goType:      "[]uuid.UUID",
+			wantType:    "UUID[]",
+			wantNotNull: false,
+		},
+		{
+			name:        "int32 slice",
+			goType:      "[]int32",
+			wantType:    "INTEGER[]",
+			wantNotNull: false,
+		},
+		{
+			name:        "float64 slice",
+			goType:      "[]float64",
+			wantType:    "DOUBLE PRECISION[]",
+			wantNotNull: false,
+		},
+		{
+			name:        "bool slice",
+			goType:      "[]bool",
+			wantType:    "BOOLEAN[]",
+			wantNotNull: false,
+		},
+		{
+			name:        "time slice",
+			goType:      "[]time.Time",
+			wantType:    "TIMESTAMP[]",
+			wantNotNull: false,
+		},
+		{
+			name:        "pointer to float64 slice",
+			goType:      "*[]float64",
+			wantType:    "DOUBLE PRECISION[]",
+			wantNotNull: false,
+		},
+		{
+			name:        "uint8 slice is bytes",
+			goType:      "[]uint8",
+			wantType:    "BYTEA",
+			wantNotNull: false,
+		},
+		{
+			name:        "slice of unknown type",
+			goType:      "[]CustomType",
+			wantType:    "TEXT[]",
+			wantNotNull: false,
+		},
+		{
+			name:        "byte slice",
+			goType:      "[]byte",
+			wantType:    "BYTEA",
+			wantNotNull: false,
+		},
+		{
+			name:        "map of any",
+			goType:      "map[string]any",
+			wantType:    "JSONB",
+			wantNotNull: false,
+		},
+		{
+			name:        "time range",
+			goType:      "pgtype.Range[time.Time]",
+			wantType:    "TSTZRANGE",
 			wantNotNull: true,
 		},
+		{
+			name:        "int64 range",
+			goType:      "pgtype.Range[int64]",
+			wantType:    "INT8RANGE",
+			wantNotNull: true,
+		},
+		{
+			name:        "pointer to timestamptz range",
+			goType:      "*pgtype.Range[pgtype.Timestamptz]",
+			wantType:    "TSTZRANGE",
+			wantNotNull: false,
+		},
+		{
+			name:        "pointer to uuid.UUID",
+			goType:      "*uuid.UUID",
+			wantType:    "UUID",
+			wantNotNull: false,
+		},
 		{
 			name:        "unknown type",
 			goType:      "CustomType",
@@ -76,6 +221,64 @@ func TestMapperMapType(t *testing.T) {
 	}
 }
 
+func TestMapperMapFieldType(t *testing.T) {
+	mapper := NewMapper()
+
+	tests := []struct {
+		name           string
+		goType         string
+		underlyingType string
+		wantType       string
+	}{
+		{
+			name:           "named string maps like string",
+			goType:         "Email",
+			underlyingType: "string",
+			wantType:       "VARCHAR(255)",
+		},
+		{
+			name:           "imported named int64 maps like int64",
+			goType:         "money.Money",
+			underlyingType: "int64",
+			wantType:       "BIGINT",
+		},
+		{
+			name:           "direct mapping wins over underlying type",
+			goType:         "json.RawMessage",
+			underlyingType: "[]byte",
+			wantType:       "JSONB",
+		},
+		{
+			name:           "slice of named int maps to an int array",
+			goType:         "[]Tier",
+			underlyingType: "[]int",
+			wantType:       "INTEGER[]",
+		},
+		{
+			name:     "slice of a mapped type",
+			goType:   "[]uuid.UUID",
+			wantType: "UUID[]",
+		},
+		{
+			name:     "unresolved named type falls back to TEXT",
+			goType:   "Email",
+			wantType: "TEXT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping := mapper.MapFieldType(tt.goType, tt.underlyingType)
+			if mapping.PostgresType != tt.wantType {
+				t.Errorf("PostgresType = %v, want %v", mapping.PostgresType, tt.wantType)
+			}
+			if mapping.GoType != tt.goType {
+				t.Errorf("GoType = %v, want %v", mapping.GoType, tt.goType)
+			}
+		})
+	}
+}
+
 func TestMapperFormatColumnDefinition(t *testing.T) {
 	mapper := NewMapper()
 
@@ -114,6 +317,125 @@ func TestMapperFormatColumnDefinition(t *testing.T) {
 			tags:    []string{},
 			want:    "VARCHAR(255) NOT NULL",
 		},
+		{
+			name:    "explicit type",
+			field:   "price",
+			mapping: TypeMapping{PostgresType: "DOUBLE PRECISION", IsNotNull: true},
+			tags:    []string{"type:NUMERIC(12,2)", "size:64"},
+			want:    "NUMERIC(12,2) NOT NULL",
+		},
+		{
+			name:    "size",
+			field:   "code",
+			mapping: TypeMapping{PostgresType: "VARCHAR(255)"},
+			tags:    []string{"unique", "size:64"},
+			want:    "VARCHAR(64) UNIQUE",
+		},
+		{
+			name:    "size on text",
+			field:   "note",
+			mapping: TypeMapping{PostgresType: "TEXT"},
+			tags:    []string{"size:16"},
+			want:    "VARCHAR(16)",
+		},
+		{
+			name:    "size on non-character type",
+			field:   "count",
+			mapping: TypeMapping{PostgresType: "BIGINT", IsNotNull: true},
+			tags:    []string{"size:16"},
+			want:    "BIGINT NOT NULL",
+		},
+		{
+			name:    "precision and scale",
+			field:   "amount",
+			mapping: TypeMapping{PostgresType: "DOUBLE PRECISION", IsNotNull: true},
+			tags:    []string{"precision:12", "scale:2"},
+			want:    "NUMERIC(12,2) NOT NULL",
+		},
+		{
+			name:    "precision only",
+			field:   "ratio",
+			mapping: TypeMapping{PostgresType: "REAL", IsNotNull: true},
+			tags:    []string{"precision:5"},
+			want:    "NUMERIC(5) NOT NULL",
+		},
+		{
+			name:    "precision on array elements",
+			field:   "weights",
+			mapping: TypeMapping{PostgresType: "DOUBLE PRECISION[]"},
+			tags:    []string{"precision:8", "scale:3"},
+			want:    "NUMERIC(8,3)[]",
+		},
+		{
+			name:    "precision on non-numeric type",
+			field:   "label",
+			mapping: TypeMapping{PostgresType: "VARCHAR(255)", IsNotNull: true},
+			tags:    []string{"precision:5"},
+			want:    "VARCHAR(255) NOT NULL",
+		},
+		{
+			name:    "timestamp precision",
+			field:   "created_at",
+			mapping: TypeMapping{PostgresType: "TIMESTAMP", IsNotNull: true},
+			tags:    []string{"precision:3"},
+			want:    "TIMESTAMP(3) NOT NULL",
+		},
+		{
+			name:    "unsigned check on column",
+			field:   "retries",
+			mapping: TypeMapping{PostgresType: "INTEGER", Constraints: []string{"CHECK ({column} >= 0)"}, IsNotNull: true},
+			tags:    []string{},
+			want:    `INTEGER NOT NULL CHECK ("retries" >= 0)`,
+		},
+		{
+			name:    "identity primary key",
+			field:   "id",
+			mapping: TypeMapping{PostgresType: "BIGINT", IsNotNull: true},
+			tags:    []string{"pk", "identity"},
+			want:    "BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY",
+		},
+		{
+			name:    "serial primary key",
+			field:   "id",
+			mapping: TypeMapping{PostgresType: "INTEGER", IsNotNull: true},
+			tags:    []string{"pk", "serial"},
+			want:    "SERIAL PRIMARY KEY",
+		},
+		{
+			name:    "bigserial column",
+			field:   "seq",
+			mapping: TypeMapping{PostgresType: "BIGINT", IsNotNull: true},
+			tags:    []string{"bigserial"},
+			want:    "BIGSERIAL NOT NULL",
+		},
+		{
+			name:    "null override",
+			field:   "nickname",
+			mapping: TypeMapping{PostgresType: "VARCHAR(255)", IsNotNull: true},
+			tags:    []string{"null"},
+			want:    "VARCHAR(255)",
+		},
+		{
+			name:    "notnull override",
+			field:   "payload",
+			mapping: TypeMapping{PostgresType: "JSONB"},
+			tags:    []string{"notnull"},
+			want:    "JSONB NOT NULL",
+		},
+		{
+			name:    "named primary key",
+			field:   "id",
+			mapping: TypeMapping{PostgresType: "BIGINT", IsNotNull: true},
+			tags:    []string{"pk", "pk_name:pk_user"},
+			want:    `BIGINT CONSTRAINT "pk_user" PRIMARY KEY`,
+		},
+		{
+			name:    "named unique and type check",
+			field:   "retries",
+			mapping: TypeMapping{PostgresType: "SMALLINT", Constraints: []string{"CHECK ({column} >= 0)"}, IsNotNull: true},
+			tags:    []string{"unique", "unique_name:uq_job_retries", "check_name:ck_job_retries"},
+			want:    `SMALLINT NOT NULL CONSTRAINT "uq_job_retries" UNIQUE CONSTRAINT "ck_job_retries" CHECK ("retries" >= 0)`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -126,6 +448,161 @@ func TestMapperFormatColumnDefinition(t *testing.T) {
 	}
 }
 
+func TestTypeTags(t *testing.T) {
+	tests := []struct {
+		name      string
+		sqlType   string
+		size      int
+		precision int
+		scale     int
+		want      []string
+	}{
+		{name: "none", want: nil},
+		{name: "all", sqlType: "CITEXT", size: 64, precision: 12, scale: 2, want: []string{"type:CITEXT", "size:64", "precision:12", "scale:2"}},
+		{name: "scale without precision", scale: 2, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TypeTags(tt.sqlType, tt.size, tt.precision, tt.scale)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TypeTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstraintTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		pk     string
+		unique string
+		check  string
+		want   []string
+	}{
+		{name: "none", want: nil},
+		{name: "all", pk: "pk_a", unique: "uq_a_b", check: "ck_a_b", want: []string{"pk_name:pk_a", "unique_name:uq_a_b", "check_name:ck_a_b"}},
+		{name: "check only", check: "ck_a_b", want: []string{"check_name:ck_a_b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ConstraintTags(tt.pk, tt.unique, tt.check)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConstraintTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapperRequiredExtension(t *testing.T) {
+	mapper := NewMapper()
+
+	tests := []struct {
+		sqlType string
+		want    string
+	}{
+		{"CITEXT", "citext"},
+		{"citext[]", "citext"},
+		{"GEOMETRY(Point,4326)", "postgis"},
+		{"UUID", ""},
+		{"TEXT[]", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sqlType, func(t *testing.T) {
+			if got := mapper.RequiredExtension(tt.sqlType); got != tt.want {
+				t.Errorf("RequiredExtension(%q) = %q, want %q", tt.sqlType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapperHasBareLiterals(t *testing.T) {
+	mapper := NewMapper()
+
+	tests := []struct {
+		sqlType string
+		want    bool
+	}{
+		{"INTEGER", true},
+		{"DOUBLE PRECISION", true},
+		{"NUMERIC(8,2)", true},
+		{"boolean", true},
+		{"VARCHAR(255)", false},
+		{"INTEGER[]", false},
+		{"UUID", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sqlType, func(t *testing.T) {
+			if got := mapper.HasBareLiterals(tt.sqlType); got != tt.want {
+				t.Errorf("HasBareLiterals(%q) = %v, want %v", tt.sqlType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapperAcceptsModifiers(t *testing.T) {
+	mapper := NewMapper()
+
+	tests := []struct {
+		sqlType       string
+		wantSize      bool
+		wantPrecision bool
+		wantScale     bool
+	}{
+		{"VARCHAR(255)", true, false, false},
+		{"TEXT", true, false, false},
+		{"TEXT[]", true, false, false},
+		{"BIGINT", false, true, true},
+		{"DOUBLE PRECISION[]", false, true, true},
+		{"TIMESTAMPTZ", false, true, false},
+		{"BOOLEAN", false, false, false},
+		{"JSONB", false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sqlType, func(t *testing.T) {
+			mapping := TypeMapping{PostgresType: tt.sqlType}
+			if got := mapper.AcceptsSize(mapping); got != tt.wantSize {
+				t.Errorf("AcceptsSize(%q) = %v, want %v", tt.sqlType, got, tt.wantSize)
+			}
+			if got := mapper.AcceptsPrecision(mapping); got != tt.wantPrecision {
+				t.Errorf("AcceptsPrecision(%q) = %v, want %v", tt.sqlType, got, tt.wantPrecision)
+			}
+			if got := mapper.AcceptsScale(mapping); got != tt.wantScale {
+				t.Errorf("AcceptsScale(%q) = %v, want %v", tt.sqlType, got, tt.wantScale)
+			}
+		})
+	}
+}
+
+func TestMapperCanHoldNull(t *testing.T) {
+	mapper := NewMapper()
+
+	tests := []struct {
+		goType string
+		want   bool
+	}{
+		{"*string", true},
+		{"sql.NullInt64", true},
+		{"sql.Null[time.Time]", true},
+		{"[]byte", true},
+		{"string", false},
+		{"time.Time", false},
+		{"json.RawMessage", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			if got := mapper.CanHoldNull(tt.goType); got != tt.want {
+				t.Errorf("CanHoldNull(%q) = %v, want %v", tt.goType, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMapperHasTag(t *testing.T) {
 	mapper := NewMapper()
 
@@ -183,6 +660,11 @@ func TestMapperGetBaseType(t *testing.T) {
 			input:    "[]int",
 			wantType: "int",
 		},
+		{
+			name:     "mapped slice type keeps full name",
+			input:    "*[]string",
+			wantType: "[]string",
+		},
 		{
 			name:     "map type returns map before split",
 			input:    "map[string]int",
@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+const directivePrefix = "//structify:"
+
+// Directive is a struct-level option from a //structify: doc comment line.
+// A line holds space-separated "key=value" pairs and bare flags, e.g.
+//
+//	//structify:table=orders schema=shop unlogged
+//
+// Values containing spaces can be double-quoted: check="start_at < end_at".
+type Directive struct {
+	Key   string
+	Value string
+
+	// Pos is the position of the comment line declaring the directive.
+	Pos token.Position
+}
+
+// parseDirectives collects the directives of a doc comment in order.
+func parseDirectives(fset *token.FileSet, doc *ast.CommentGroup) []Directive {
+	if doc == nil {
+		return nil
+	}
+
+	var directives []Directive
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(comment.Text)
+		if !strings.HasPrefix(text, directivePrefix) {
+			continue
+		}
+		for _, token := range splitDirectiveTokens(strings.TrimPrefix(text, directivePrefix)) {
+			key, value, _ := strings.Cut(token, "=")
+			if strings.HasPrefix(value, `"`) {
+				if unquoted, err := strconv.Unquote(value); err == nil {
+					value = unquoted
+				}
+			}
+			directives = append(directives, Directive{Key: key, Value: value, Pos: fset.Position(comment.Pos())})
+		}
+	}
+	return directives
+}
+
+// splitDirectiveTokens splits a directive line on spaces outside of
+// double quotes.
+func splitDirectiveTokens(line string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && inQuotes && i+1 < len(line):
+			current.WriteByte(c)
+			i++
+			current.WriteByte(line[i])
+		case c == '"':
+			inQuotes = !inQuotes
+			current.WriteByte(c)
+		case (c == ' ' || c == '\t') && !inQuotes:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteByte(c)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// sqlLines returns the values of the //sql: lines of a doc comment in
+// order. Quoted values are unquoted, so escapes like \" resolve.
+func sqlLines(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+
+	var lines []string
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(comment.Text)
+		sqlVal, ok := strings.CutPrefix(text, "//sql:")
+		if !ok {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(sqlVal); err == nil {
+			sqlVal = unquoted
+		} else {
+			sqlVal = strings.Trim(sqlVal, `"`)
+		}
+		lines = append(lines, sqlVal)
+	}
+	return lines
+}
+
+// commentText returns the text of a doc comment without its comment markers,
+// directive lines such as //structify: and //sql: lines, or "" for none.
+func commentText(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+
+	text := &ast.CommentGroup{}
+	for _, comment := range doc.List {
+		if !strings.HasPrefix(strings.TrimSpace(comment.Text), "//sql:") {
+			text.List = append(text.List, comment)
+		}
+	}
+	return strings.TrimSpace(text.Text())
+}
+
+// fieldDoc returns the doc comment of a struct field, falling back to its
+// trailing line comment.
+func fieldDoc(field *ast.Field) string {
+	if text := commentText(field.Doc); text != "" {
+		return text
+	}
+	return commentText(field.Comment)
+}
@@ -0,0 +1,242 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/n0xum/structify/internal/util"
+)
+
+// maxEmbedDepth bounds how deep embedded structs are followed, guarding
+// against pathological pointer cycles.
+const maxEmbedDepth = 8
+
+// embedScope describes where the fields of an embedded struct end up in the
+// parent: anonymous embeds promote their fields unchanged, while embeds of
+// named fields (db:"embed") prefix field names and Go selectors.
+type embedScope struct {
+	namePrefix     string // prepended to field names, e.g. "Billing"
+	selectorPrefix string // Go selector to the embedded value, e.g. "Billing."
+	columnPrefix   string // prepended to column names, e.g. "bill_"
+	depth          int
+}
+
+// parseEmbedTag reports whether a db tag asks for the field's struct to be
+// inlined and which column prefix it sets, e.g. db:"embed,prefix:addr_".
+func parseEmbedTag(dbTag string) (embed bool, prefix string) {
+	for _, part := range strings.Split(dbTag, ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "embed":
+			embed = true
+		case strings.HasPrefix(part, "prefix:"):
+			prefix = strings.TrimPrefix(part, "prefix:")
+		}
+	}
+	return embed, prefix
+}
+
+// parseColumnTag returns the column name set by a column:name db tag.
+func parseColumnTag(dbTag string) string {
+	for _, part := range strings.Split(dbTag, ",") {
+		if column, ok := strings.CutPrefix(strings.TrimSpace(part), "column:"); ok {
+			return column
+		}
+	}
+	return ""
+}
+
+// embeddedFields returns the fields of the struct type behind expr, inlined
+// according to scope. It returns nil when the type could not be resolved to a
+// struct; an embed from a package that was not loaded is reported on s.
+func (p *Parser) embeddedFields(s *Struct, expr ast.Expr, scope embedScope) []Field {
+	var t types.Type
+	if p.info != nil {
+		t = p.info.TypeOf(expr)
+	}
+	return p.inlineStruct(s, t, exprToFullString(expr), p.fset.Position(expr.Pos()), scope)
+}
+
+// inlineStruct returns the fields of t, the type of the embed named name at
+// pos. Embeds whose fields cannot be inlined are reported on s.
+func (p *Parser) inlineStruct(s *Struct, t types.Type, name string, pos token.Position, scope embedScope) []Field {
+	report := func(format string, args ...any) []Field {
+		s.Diagnostics = append(s.Diagnostics, Diagnostic{Pos: pos, Message: fmt.Sprintf(format, args...)})
+		return nil
+	}
+	switch {
+	case isInvalid(t):
+		return report("embedded type %s could not be resolved, so its fields are not inlined", name)
+	case scope.depth >= maxEmbedDepth:
+		return report("embedded type %s is nested more than %d levels deep, so its fields are not inlined", name, maxEmbedDepth)
+	}
+	st := structOf(t)
+	if st == nil {
+		return report("embedded type %s is not a struct, so it is not inlined", name)
+	}
+	p.markEmbedded(s, t)
+
+	var fields []Field
+	for i := 0; i < st.NumFields(); i++ {
+		v := st.Field(i)
+		dbTag := parseDBTag(st.Tag(i))
+		if dbTag == "-" {
+			continue
+		}
+
+		embed, prefix := parseEmbedTag(dbTag)
+		switch {
+		case v.Embedded():
+			// Promoted fields keep their names and selectors.
+			nested := scope
+			nested.columnPrefix += prefix
+			nested.depth++
+			fields = append(fields, p.inlineStruct(s, v.Type(), v.Name(), p.fset.Position(v.Pos()), nested)...)
+			continue
+		case !v.Exported():
+			continue
+		case embed:
+			if prefix == "" {
+				prefix = util.ToSnakeCase(v.Name()) + "_"
+			}
+			nested := embedScope{
+				namePrefix:     scope.namePrefix + v.Name(),
+				selectorPrefix: scope.selectorPrefix + v.Name() + ".",
+				columnPrefix:   scope.columnPrefix + prefix,
+				depth:          scope.depth + 1,
+			}
+			// A field that cannot be inlined stays a column of its own
+			if inlined := p.inlineStruct(s, v.Type(), types.TypeString(v.Type(), p.qualifier), p.fset.Position(v.Pos()), nested); len(inlined) > 0 {
+				fields = append(fields, inlined...)
+				continue
+			}
+		}
+
+		f := Field{
+			Name:        scope.namePrefix + v.Name(),
+			Type:        p.fieldTypeString(v),
+			DatabaseTag: dbTag,
+			EnumValues:  p.enumValuesOf(v.Type()),
+			Pos:         p.fset.Position(v.Pos()),
+		}
+		if decl, ok := p.fieldDecls[v]; ok {
+			f.Doc = fieldDoc(decl)
+		}
+		if underlying := p.underlyingTypeString(v.Type()); underlying != f.Type {
+			f.UnderlyingType = underlying
+		}
+		if scope.columnPrefix != "" {
+			column := util.ToSnakeCase(v.Name())
+			if override := parseColumnTag(dbTag); override != "" {
+				column = override
+			}
+			f.Column = scope.columnPrefix + column
+		}
+		if scope.selectorPrefix != "" {
+			f.Selector = scope.selectorPrefix + v.Name()
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// markEmbedded records the named struct type behind t as inlined into s,
+// unless it is s itself.
+func (p *Parser) markEmbedded(s *Struct, t types.Type) {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return
+	}
+	if key := named.Obj().Pkg().Name() + "." + named.Obj().Name(); key != s.PackageName+"."+s.Name {
+		p.embedded[key] = true
+	}
+}
+
+// dropEmbedded removes the structs inlined into other structs: they hold
+// shared columns or value objects rather than rows of a table. A struct
+// with a table= directive keeps its table.
+func (p *Parser) dropEmbedded() {
+	for pkg, structs := range p.structs {
+		var kept []*Struct
+		for _, s := range structs {
+			if !p.embedded[pkg+"."+s.Name] || hasDirective(s.Directives, "table") {
+				kept = append(kept, s)
+			}
+		}
+		if len(kept) == 0 {
+			delete(p.structs, pkg)
+		} else {
+			p.structs[pkg] = kept
+		}
+	}
+}
+
+// hasDirective reports whether directives include key.
+func hasDirective(directives []Directive, key string) bool {
+	for _, d := range directives {
+		if d.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldTypeString spells the type of an inlined field. Fields declared in the
+// current package keep their source spelling, which stays meaningful even
+// when imports were not loaded; fields of imported packages are fully typed.
+func (p *Parser) fieldTypeString(v *types.Var) string {
+	if decl, ok := p.fieldDecls[v]; ok {
+		return exprToFullString(decl.Type)
+	}
+	return types.TypeString(v.Type(), p.qualifier)
+}
+
+// collectFieldDecls indexes the named struct fields declared in files.
+func (p *Parser) collectFieldDecls(files []*ast.File) map[*types.Var]*ast.Field {
+	decls := make(map[*types.Var]*ast.Field)
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			st, ok := n.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range st.Fields.List {
+				for _, name := range field.Names {
+					if v, ok := p.info.Defs[name].(*types.Var); ok {
+						decls[v] = field
+					}
+				}
+			}
+			return true
+		})
+	}
+	return decls
+}
+
+// isInvalid reports whether t, or the type it points to, is missing or
+// failed to type-check, as for a type from a package that was not loaded.
+func isInvalid(t types.Type) bool {
+	if t == nil {
+		return true
+	}
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	basic, ok := types.Unalias(t).(*types.Basic)
+	return ok && basic.Kind() == types.Invalid
+}
+
+// structOf returns the struct type behind t, dereferencing one pointer level.
+func structOf(t types.Type) *types.Struct {
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	st, _ := types.Unalias(t).Underlying().(*types.Struct)
+	return st
+}
@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"sort"
+	"strconv"
+)
+
+// typeOf returns the checked type of expr, or nil without type information.
+func (p *Parser) typeOf(expr ast.Expr) types.Type {
+	if p.info == nil {
+		return nil
+	}
+	return p.info.TypeOf(expr)
+}
+
+// enumValuesOf returns the values of the constants declared with the named
+// type behind t (dereferencing a pointer) in the package that declares the
+// type, ordered by declaration. For
+//
+//	type OrderStatus string
+//	const (
+//		StatusPending OrderStatus = "pending"
+//		StatusShipped OrderStatus = "shipped"
+//	)
+//
+// it returns ["pending", "shipped"].
+func (p *Parser) enumValuesOf(t types.Type) []string {
+	if t == nil {
+		return nil
+	}
+	if ptr, ok := types.Unalias(t).(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return nil
+	}
+	if _, ok := named.Underlying().(*types.Basic); !ok {
+		return nil
+	}
+
+	scope := named.Obj().Pkg().Scope()
+	var consts []*types.Const
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), named) {
+			continue
+		}
+		consts = append(consts, c)
+	}
+	if len(consts) == 0 {
+		return nil
+	}
+
+	// Scope names are sorted alphabetically; restore declaration order.
+	sort.SliceStable(consts, func(i, j int) bool {
+		return consts[i].Pos() < consts[j].Pos()
+	})
+
+	values := make([]string, 0, len(consts))
+	seen := make(map[string]bool)
+	for _, c := range consts {
+		value := constantString(c.Val())
+		if !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// constantString renders a constant value as it would be stored in the
+// column: strings unquoted, floats in decimal notation (0.1, not the exact
+// fraction 1/10), integers and booleans as written in SQL.
+func constantString(v constant.Value) string {
+	switch v.Kind() {
+	case constant.String:
+		return constant.StringVal(v)
+	case constant.Float:
+		f, _ := constant.Float64Val(v)
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return v.ExactString()
+}
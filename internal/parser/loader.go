@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ParsePackages loads the Go packages matched by patterns, type-checks them
+// together with their imports and extracts structs and interfaces.
+// A pattern is a directory, optionally suffixed with "/..." to include all
+// subdirectories (e.g. "./models/...").
+func (p *Parser) ParsePackages(patterns []string) error {
+	p.reset()
+	p.readFile = readRelativeFile
+
+	dirs, err := expandPatterns(patterns)
+	if err != nil {
+		return err
+	}
+
+	imp := importer.ForCompiler(p.fset, "source", nil)
+	for _, dir := range dirs {
+		bp, err := build.Default.ImportDir(dir, 0)
+		if err != nil {
+			var noGo *build.NoGoError
+			if errors.As(err, &noGo) {
+				continue
+			}
+			return fmt.Errorf("load package %s: %w", dir, err)
+		}
+
+		files := make([]*ast.File, 0, len(bp.GoFiles))
+		for _, name := range bp.GoFiles {
+			path := filepath.Join(dir, name)
+			f, err := parser.ParseFile(p.fset, path, nil, parser.ParseComments)
+			if err != nil {
+				return fmt.Errorf("parse file %s: %w", path, err)
+			}
+			files = append(files, f)
+		}
+		if len(files) == 0 {
+			continue
+		}
+
+		pkgPath := bp.ImportPath
+		if pkgPath == "" || pkgPath == "." {
+			pkgPath = bp.Name
+		}
+		p.walkPackage(pkgPath, files, imp)
+	}
+	p.dropEmbedded()
+	return p.err
+}
+
+// expandPatterns resolves package patterns to the directories they match.
+// Hidden directories, "_"-prefixed directories, testdata and vendor are
+// skipped when walking a "/..." pattern, mirroring the go tool.
+func expandPatterns(patterns []string) ([]string, error) {
+	var dirs []string
+	seen := make(map[string]bool)
+	add := func(dir string) {
+		dir = filepath.Clean(dir)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, pattern := range patterns {
+		root, recursive := strings.CutSuffix(pattern, "...")
+		if recursive {
+			root = strings.TrimSuffix(root, "/")
+			if root == "" {
+				root = "."
+			}
+		}
+
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("load package %s: %w", pattern, err)
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("load package %s: not a directory", pattern)
+		}
+
+		if !recursive {
+			add(root)
+			continue
+		}
+
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			name := d.Name()
+			if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor") {
+				return filepath.SkipDir
+			}
+			add(path)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("load package %s: %w", pattern, err)
+		}
+	}
+	return dirs, nil
+}
+
+// groupByPackage groups files by their package clause, keeping the order in
+// which each package was first seen.
+func groupByPackage(files []*ast.File) [][]*ast.File {
+	var groups [][]*ast.File
+	index := make(map[string]int)
+	for _, f := range files {
+		name := f.Name.Name
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], f)
+	}
+	return groups
+}
+
+// offlineImporter refuses every import so that loose files can be
+// type-checked without a Go toolchain. Types from other packages stay
+// unresolved and fall back to their spelling in the source.
+type offlineImporter struct{}
+
+func (offlineImporter) Import(path string) (*types.Package, error) {
+	return nil, fmt.Errorf("import %q: imports are not loaded in file mode", path)
+}
+
+// resolveUnderlyingType returns the underlying type of a field type
+// expression, or "" when it is unknown or identical to the declared type.
+func (p *Parser) resolveUnderlyingType(expr ast.Expr, declared string) string {
+	if p.info == nil {
+		return ""
+	}
+	t := p.info.TypeOf(expr)
+	if t == nil {
+		return ""
+	}
+	resolved := p.underlyingTypeString(t)
+	if resolved == declared {
+		return ""
+	}
+	return resolved
+}
+
+// underlyingTypeString renders t with every named type that wraps a basic,
+// slice, array, map or pointer type replaced by its underlying type. Named
+// structs and interfaces (time.Time, sql.NullString, ...) are kept because
+// they carry their own column mappings. Returns "" if t is not fully resolved.
+func (p *Parser) underlyingTypeString(t types.Type) string {
+	switch v := types.Unalias(t).(type) {
+	case *types.Basic:
+		if v.Kind() == types.Invalid {
+			return ""
+		}
+		return v.Name()
+	case *types.Pointer:
+		if elem := p.underlyingTypeString(v.Elem()); elem != "" {
+			return "*" + elem
+		}
+		return ""
+	case *types.Slice:
+		if elem := p.underlyingTypeString(v.Elem()); elem != "" {
+			return "[]" + elem
+		}
+		return ""
+	case *types.Named:
+		switch v.Underlying().(type) {
+		case *types.Struct, *types.Interface:
+			return types.TypeString(v, p.qualifier)
+		}
+		return p.underlyingTypeString(v.Underlying())
+	}
+	return types.TypeString(t, p.qualifier)
+}
+
+// qualifier prints types from other packages with their package name
+// (e.g. "time.Time"), matching how field types are spelled in source.
+func (p *Parser) qualifier(other *types.Package) string {
+	if other == p.pkg {
+		return ""
+	}
+	return other.Name()
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func findParsedStruct(p *Parser, name string) *Struct {
+	for _, structs := range p.GetStructs() {
+		for _, s := range structs {
+			if s.Name == name {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+func underlyingTypes(s *Struct) map[string]string {
+	result := make(map[string]string)
+	for _, f := range s.Fields {
+		result[f.Name] = f.UnderlyingType
+	}
+	return result
+}
+
+func TestParsePackagesResolvesTypes(t *testing.T) {
+	p := New()
+
+	if err := p.ParsePackages([]string{"../../test/fixtures/typed"}); err != nil {
+		t.Fatalf("ParsePackages() error = %v", err)
+	}
+
+	account := findParsedStruct(p, "Account")
+	if account == nil {
+		t.Fatal("Account struct not found")
+	}
+	if account.PackageName != "typed" {
+		t.Errorf("PackageName = %q, want typed", account.PackageName)
+	}
+
+	got := underlyingTypes(account)
+	want := map[string]string{
+		"ID":        "",
+		"Email":     "string",
+		"Backup":    "*string",
+		"Balance":   "int64",
+		"Tags":      "[]string",
+		"CreatedAt": "",
+	}
+	for name, wantType := range want {
+		if got[name] != wantType {
+			t.Errorf("field %s UnderlyingType = %q, want %q", name, got[name], wantType)
+		}
+	}
+}
+
+func TestParsePackagesRecursivePattern(t *testing.T) {
+	p := New()
+
+	if err := p.ParsePackages([]string{"../../test/fixtures/typed/..."}); err != nil {
+		t.Fatalf("ParsePackages() error = %v", err)
+	}
+	if findParsedStruct(p, "Account") == nil {
+		t.Error("Account struct not found via recursive pattern")
+	}
+}
+
+func TestParsePackagesNonExistent(t *testing.T) {
+	p := New()
+
+	if err := p.ParsePackages([]string{"./does-not-exist/..."}); err == nil {
+		t.Error("ParsePackages() should return error for non-existent directory")
+	}
+}
+
+func TestParseFilesResolvesSiblingTypes(t *testing.T) {
+	p := New()
+
+	err := p.ParseFiles([]string{
+		"../../test/fixtures/typed/types.go",
+		"../../test/fixtures/typed/account.go",
+	})
+	if err != nil {
+		t.Fatalf("ParseFiles() error = %v", err)
+	}
+
+	account := findParsedStruct(p, "Account")
+	if account == nil {
+		t.Fatal("Account struct not found")
+	}
+
+	got := underlyingTypes(account)
+	if got["Email"] != "string" {
+		t.Errorf("Email UnderlyingType = %q, want string", got["Email"])
+	}
+	// Imports are not loaded in file mode, so money.Money stays unresolved.
+	if got["Balance"] != "" {
+		t.Errorf("Balance UnderlyingType = %q, want empty", got["Balance"])
+	}
+}
+
+func TestExpandPatterns(t *testing.T) {
+	dirs, err := expandPatterns([]string{"../../test/fixtures/typed/...", "../../test/fixtures/typed"})
+	if err != nil {
+		t.Fatalf("expandPatterns() error = %v", err)
+	}
+	if len(dirs) != 2 {
+		t.Errorf("expandPatterns() returned %d dirs, want 2 (deduplicated): %v", len(dirs), dirs)
+	}
+}
+
+func TestParseTypedConstantEnums(t *testing.T) {
+	p := New()
+
+	err := p.ParseFiles([]string{
+		"../../test/fixtures/typed/types.go",
+		"../../test/fixtures/typed/status.go",
+		"../../test/fixtures/typed/account.go",
+	})
+	if err != nil {
+		t.Fatalf("ParseFiles() error = %v", err)
+	}
+
+	account := findParsedStruct(p, "Account")
+	if account == nil {
+		t.Fatal("Account struct not found")
+	}
+
+	got := make(map[string][]string)
+	for _, f := range account.Fields {
+		got[f.Name] = f.EnumValues
+	}
+
+	tests := []struct {
+		field string
+		want  []string
+	}{
+		{"Status", []string{"pending", "active", "closed"}},
+		{"Tier", []string{"0", "1", "2"}},
+		{"Discount", []string{"0", "0.1", "0.25"}},
+		{"Legacy", []string{"pending", "active", "closed"}},
+		{"Email", nil},
+		{"ID", nil},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(got[tt.field], tt.want) {
+			t.Errorf("field %s EnumValues = %v, want %v", tt.field, got[tt.field], tt.want)
+		}
+	}
+}
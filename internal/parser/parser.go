@@ -5,7 +5,11 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
-	"strconv"
+	"go/types"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/n0xum/structify/internal/util"
@@ -15,6 +19,36 @@ type Field struct {
 	Name        string
 	Type        string
 	DatabaseTag string
+
+	// UnderlyingType is the resolved Go type behind Type when Type is a
+	// named type (e.g. "string" for `type Email string`). Empty when the
+	// type could not be resolved or already is its own underlying type.
+	UnderlyingType string
+
+	// Column is the column name of a field inlined from an embedded struct
+	// with a column prefix; empty means the name is derived from Name.
+	Column string
+
+	// Selector is the Go selector path that reaches an inlined field from
+	// the parent struct (e.g. "Address.Street"); empty means Name.
+	Selector string
+
+	// EnumValues holds the values of the typed constants declared for the
+	// field's named type, in declaration order (e.g. the const block of
+	// `type OrderStatus string`). Empty when the type has no constants.
+	EnumValues []string
+
+	// Pos is the position of the field name.
+	Pos token.Position
+
+	// TagPos is the position of the db tag value inside the struct tag
+	// (the p of `db:"pk"`); invalid when the field has no db tag or its
+	// tag is not available, e.g. for fields inlined from another package.
+	TagPos token.Position
+
+	// Doc is the text of the field's doc comment, or of its line comment
+	// when it has no doc comment.
+	Doc string
 }
 
 type Struct struct {
@@ -22,6 +56,26 @@ type Struct struct {
 	Fields      []Field
 	PackageName string
 	TableName   string
+	// Directives holds the //structify: options from the type's doc comment
+	Directives []Directive
+	// Doc is the text of the type's doc comment without directive lines.
+	Doc string
+	// PackageSchema is the default schema of the struct's package, from a
+	// //structify:schema=name directive on a package clause.
+	PackageSchema string
+	// SQL is the query defining a view: the //sql: lines of the doc comment
+	// joined by newlines, or the contents of the file named by a
+	// //structify:sql_file= directive.
+	SQL string
+	// Diagnostics holds the problems found while parsing the struct, such
+	// as an embedded type whose fields could not be inlined.
+	Diagnostics []Diagnostic
+}
+
+// Diagnostic is a problem in a struct's source at the position it occurs.
+type Diagnostic struct {
+	Pos     token.Position
+	Message string
 }
 
 type Interface struct {
@@ -54,6 +108,31 @@ type Parser struct {
 	structs    map[string][]*Struct
 	interfaces map[string][]*Interface
 	pkgName    string
+
+	// pkgSchema is the default schema of the package currently being walked.
+	pkgSchema string
+
+	// readFile reads a file named relative to the Go file referencing it,
+	// such as a view's sql_file; nil when no files can be read, as for
+	// in-memory sources.
+	readFile func(goFile, name string) ([]byte, error)
+
+	// err is the first error found while walking the files.
+	err error
+
+	// pkg and info hold the type-checking result of the package currently
+	// being walked; both are nil when no type information is available.
+	pkg  *types.Package
+	info *types.Info
+
+	// fieldDecls maps the struct fields declared in the current package to
+	// their declarations, so inlined fields keep their source spelling and
+	// doc comments.
+	fieldDecls map[*types.Var]*ast.Field
+
+	// embedded holds the struct types inlined into another struct, as
+	// "package.Type"; they get no table of their own.
+	embedded map[string]bool
 }
 
 type visitor struct {
@@ -79,12 +158,24 @@ func (v *visitor) Visit(node ast.Node) ast.Visitor {
 					continue
 				}
 
+				// A lone spec's doc comment is attached to the GenDecl.
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+
 				switch t := typeSpec.Type.(type) {
 				case *ast.StructType:
+					directives := parseDirectives(v.p.fset, doc)
 					s := &Struct{
 						Name:        typeName,
 						PackageName: v.p.pkgName,
 						TableName:   util.ToSnakeCase(typeName),
+						Directives:  directives,
+						Doc:         commentText(doc),
+
+						PackageSchema: v.p.pkgSchema,
+						SQL:           v.p.viewSQL(doc, directives),
 					}
 					v.p.extractFields(t, s)
 					if len(s.Fields) > 0 {
@@ -117,67 +208,244 @@ func New() *Parser {
 		fset:       token.NewFileSet(),
 		structs:    make(map[string][]*Struct),
 		interfaces: make(map[string][]*Interface),
+		embedded:   make(map[string]bool),
 	}
 }
 
 func (p *Parser) ParseFiles(paths []string) error {
-	p.structs = make(map[string][]*Struct)
-	p.interfaces = make(map[string][]*Interface)
-	p.pkgName = ""
+	p.reset()
+	p.readFile = readRelativeFile
+	files := make([]*ast.File, 0, len(paths))
 	for _, path := range paths {
-		if err := p.parseFile(path); err != nil {
+		f, err := parser.ParseFile(p.fset, path, nil, parser.ParseComments)
+		if err != nil {
 			return fmt.Errorf("parse file %s: %w", path, err)
 		}
+		files = append(files, f)
 	}
-	return nil
+
+	p.walkFiles(files)
+	return p.err
 }
 
-func (p *Parser) parseFile(path string) error {
-	f, err := parser.ParseFile(p.fset, path, nil, parser.ParseComments)
+// ParseSource parses a single Go file held in memory. The filename is only
+// used in positions and error messages; nothing is read from disk.
+func (p *Parser) ParseSource(filename string, src []byte) error {
+	p.reset()
+	f, err := parser.ParseFile(p.fset, filename, src, parser.ParseComments)
 	if err != nil {
-		return err
+		return fmt.Errorf("parse file %s: %w", filename, err)
 	}
+	p.walkFiles([]*ast.File{f})
+	return p.err
+}
 
-	// Store the package name from the AST file
-	if f.Name != nil {
-		p.pkgName = f.Name.Name
+// ParseFS parses the named files from fsys, e.g. an embed.FS.
+func (p *Parser) ParseFS(fsys fs.FS, paths []string) error {
+	p.reset()
+	p.readFile = func(goFile, name string) ([]byte, error) {
+		return fs.ReadFile(fsys, path.Join(path.Dir(goFile), name))
+	}
+	files := make([]*ast.File, 0, len(paths))
+	for _, path := range paths {
+		src, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return fmt.Errorf("read file %s: %w", path, err)
+		}
+		f, err := parser.ParseFile(p.fset, path, src, parser.ParseComments)
+		if err != nil {
+			return fmt.Errorf("parse file %s: %w", path, err)
+		}
+		files = append(files, f)
 	}
 
-	ast.Walk(&visitor{p: p}, f)
+	p.walkFiles(files)
+	return p.err
+}
 
-	return nil
+// walkFiles type-checks files sharing a package clause together so that
+// named types declared in sibling files resolve. Imports are not loaded.
+func (p *Parser) walkFiles(files []*ast.File) {
+	for _, group := range groupByPackage(files) {
+		p.walkPackage(group[0].Name.Name, group, offlineImporter{})
+	}
+	p.dropEmbedded()
 }
 
-func (p *Parser) extractFields(structType *ast.StructType, s *Struct) {
-	for _, field := range structType.Fields.List {
-		if field.Names == nil {
+func (p *Parser) reset() {
+	p.structs = make(map[string][]*Struct)
+	p.interfaces = make(map[string][]*Interface)
+	p.embedded = make(map[string]bool)
+	p.pkgName = ""
+	p.pkg = nil
+	p.info = nil
+	p.fieldDecls = nil
+	p.readFile = nil
+	p.err = nil
+}
+
+// readRelativeFile reads name from disk, relative to the directory of
+// goFile unless it is absolute.
+func readRelativeFile(goFile, name string) ([]byte, error) {
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(filepath.Dir(goFile), name)
+	}
+	return os.ReadFile(name)
+}
+
+// viewSQL returns the query defining a view declared in doc: the contents
+// of the file named by a sql_file= directive, or else the //sql: lines.
+// A file that cannot be read is recorded in p.err.
+func (p *Parser) viewSQL(doc *ast.CommentGroup, directives []Directive) string {
+	for _, d := range directives {
+		if d.Key != "sql_file" {
 			continue
 		}
+		goFile := p.fset.Position(doc.Pos()).Filename
+		if p.readFile == nil {
+			p.fail(fmt.Errorf("%s: sql_file %s: files cannot be read for in-memory sources", goFile, d.Value))
+			return ""
+		}
+		src, err := p.readFile(goFile, d.Value)
+		if err != nil {
+			p.fail(fmt.Errorf("%s: read sql_file: %w", goFile, err))
+			return ""
+		}
+		return strings.TrimSpace(string(src))
+	}
+	return strings.Join(sqlLines(doc), "\n")
+}
 
-		fieldName := field.Names[0].Name
-		if !token.IsExported(fieldName) && len(field.Names) == 1 {
-			continue
+// fail records err unless an earlier error was recorded.
+func (p *Parser) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// walkPackage type-checks files as one package and extracts their structs
+// and interfaces. Type errors are tolerated: whatever resolved is used.
+func (p *Parser) walkPackage(path string, files []*ast.File, imp types.Importer) {
+	p.info = &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{
+		Importer: imp,
+		Error:    func(error) {},
+	}
+	p.pkg, _ = conf.Check(path, p.fset, files, p.info)
+	p.fieldDecls = p.collectFieldDecls(files)
+	p.pkgSchema = packageSchema(p.fset, files)
+
+	for _, f := range files {
+		// Store the package name from the AST file
+		if f.Name != nil {
+			p.pkgName = f.Name.Name
 		}
+		ast.Walk(&visitor{p: p}, f)
+	}
 
-		f := Field{
-			Name: fieldName,
+	p.pkg = nil
+	p.info = nil
+	p.fieldDecls = nil
+	p.pkgSchema = ""
+}
+
+// packageSchema returns the schema= directive declared on the package
+// clause of any of files, or "" for none.
+func packageSchema(fset *token.FileSet, files []*ast.File) string {
+	for _, f := range files {
+		for _, d := range parseDirectives(fset, f.Doc) {
+			if d.Key == "schema" && d.Value != "" {
+				return d.Value
+			}
 		}
+	}
+	return ""
+}
 
+func (p *Parser) extractFields(structType *ast.StructType, s *Struct) {
+	for _, field := range structType.Fields.List {
+		var dbTag string
+		var tagPos token.Position
 		if field.Tag != nil {
 			tag := strings.Trim(field.Tag.Value, "`")
-			f.DatabaseTag = parseDBTag(tag)
+			dbTag = parseDBTag(tag)
+			tagPos = p.dbTagPos(field.Tag)
 		}
 
+		if field.Names == nil {
+			// Embedded struct: its fields are inlined into the parent table.
+			if dbTag != "-" {
+				_, prefix := parseEmbedTag(dbTag)
+				s.Fields = append(s.Fields, p.embeddedFields(s, field.Type, embedScope{columnPrefix: prefix})...)
+			}
+			continue
+		}
+
+		var fieldType, underlyingType string
+		var enumValues []string
 		if field.Type != nil {
-			f.Type = exprToFullString(field.Type)
+			fieldType = exprToFullString(field.Type)
+			underlyingType = p.resolveUnderlyingType(field.Type, fieldType)
+			enumValues = p.enumValuesOf(p.typeOf(field.Type))
 		}
+		if fieldType == "" {
+			fieldType = "interface{}"
+		}
+
+		// A declaration like `Lat, Lng float64` yields one field per name,
+		// all sharing the same type and tag.
+		for _, name := range field.Names {
+			fieldName := name.Name
+			if !token.IsExported(fieldName) {
+				continue
+			}
+
+			if embed, prefix := parseEmbedTag(dbTag); embed {
+				if prefix == "" {
+					prefix = util.ToSnakeCase(fieldName) + "_"
+				}
+				scope := embedScope{
+					namePrefix:     fieldName,
+					selectorPrefix: fieldName + ".",
+					columnPrefix:   prefix,
+				}
+				if inlined := p.embeddedFields(s, field.Type, scope); len(inlined) > 0 {
+					s.Fields = append(s.Fields, inlined...)
+					continue
+				}
+			}
 
-		if f.Type == "" {
-			f.Type = "interface{}"
+			s.Fields = append(s.Fields, Field{
+				Name:           fieldName,
+				Type:           fieldType,
+				DatabaseTag:    dbTag,
+				UnderlyingType: underlyingType,
+				EnumValues:     enumValues,
+				Pos:            p.fset.Position(name.Pos()),
+				TagPos:         tagPos,
+				Doc:            fieldDoc(field),
+			})
 		}
+	}
+}
 
-		s.Fields = append(s.Fields, f)
+// dbTagPos returns the position of the db tag value inside a raw struct tag
+// literal. It is invalid when the literal holds no db key.
+func (p *Parser) dbTagPos(tag *ast.BasicLit) token.Position {
+	if !strings.HasPrefix(tag.Value, "`") {
+		return token.Position{}
+	}
+	const key = `db:"`
+	for i := 1; i+len(key) <= len(tag.Value); i++ {
+		prev := tag.Value[i-1]
+		if (prev == '`' || prev == ' ') && strings.HasPrefix(tag.Value[i:], key) {
+			return p.fset.Position(tag.Pos() + token.Pos(i+len(key)))
+		}
 	}
+	return token.Position{}
 }
 
 func (p *Parser) extractMethods(ifaceType *ast.InterfaceType, iface *Interface) {
@@ -198,22 +466,9 @@ func (p *Parser) extractMethods(ifaceType *ast.InterfaceType, iface *Interface)
 			Name: field.Names[0].Name,
 		}
 
-		// Parse //sql:"..." from Doc comments
-		if field.Doc != nil {
-			for _, comment := range field.Doc.List {
-				text := strings.TrimSpace(comment.Text)
-				if strings.HasPrefix(text, "//sql:") {
-					sqlVal := strings.TrimPrefix(text, "//sql:")
-					// Use strconv.Unquote so that escape sequences like \"
-					// inside the SQL string are properly resolved.
-					if unquoted, err := strconv.Unquote(sqlVal); err == nil {
-						sqlVal = unquoted
-					} else {
-						sqlVal = strings.Trim(sqlVal, `"`)
-					}
-					m.SQLComment = sqlVal
-				}
-			}
+		// Parse //sql:"..." from Doc comments; the last line wins
+		if lines := sqlLines(field.Doc); len(lines) > 0 {
+			m.SQLComment = lines[len(lines)-1]
 		}
 
 		// Extract parameters (skip first ctx context.Context)
@@ -338,6 +593,14 @@ func exprToFullString(expr ast.Expr) string {
 		return "interface{}"
 	case *ast.Ellipsis:
 		return "..." + exprToFullString(v.Elt)
+	case *ast.IndexExpr:
+		return exprToFullString(v.X) + "[" + exprToFullString(v.Index) + "]"
+	case *ast.IndexListExpr:
+		args := make([]string, len(v.Indices))
+		for i, index := range v.Indices {
+			args[i] = exprToFullString(index)
+		}
+		return exprToFullString(v.X) + "[" + strings.Join(args, ", ") + "]"
 	default:
 		return "any"
 	}
@@ -1,8 +1,13 @@
 package parser
 
 import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/n0xum/structify/internal/util"
 )
@@ -1301,3 +1306,491 @@ func TestParseDBTagNoDBKey(t *testing.T) {
 		t.Errorf("parseDBTag with no db key = %q, want empty", result)
 	}
 }
+
+func TestParseEmbeddedStructs(t *testing.T) {
+	p := New()
+
+	if err := p.ParseFiles([]string{"../../test/fixtures/embedded.go"}); err != nil {
+		t.Fatalf("ParseFiles() error = %v", err)
+	}
+
+	var customer *Struct
+	for _, s := range p.GetStructs()["fixtures"] {
+		if s.Name == "Customer" {
+			customer = s
+		}
+	}
+	if customer == nil {
+		t.Fatal("Customer struct not found")
+	}
+
+	want := []Field{
+		{Name: "ID", Type: "int64", DatabaseTag: "pk"},
+		{Name: "CreatedAt", Type: "time.Time"},
+		{Name: "UpdatedAt", Type: "time.Time"},
+		{Name: "Name", Type: "string"},
+		{Name: "ShippingStreet", Type: "string", Column: "ship_street", Selector: "Shipping.Street"},
+		{Name: "ShippingCity", Type: "string", DatabaseTag: "index", Column: "ship_city", Selector: "Shipping.City"},
+		{Name: "BillingStreet", Type: "string", Column: "billing_street", Selector: "Billing.Street"},
+		{Name: "BillingCity", Type: "string", DatabaseTag: "index", Column: "billing_city", Selector: "Billing.City"},
+		{Name: "Notes", Type: "string", DatabaseTag: "-"},
+	}
+
+	if len(customer.Fields) != len(want) {
+		t.Fatalf("got %d fields, want %d: %+v", len(customer.Fields), len(want), customer.Fields)
+	}
+	if len(customer.Diagnostics) != 0 {
+		t.Errorf("Diagnostics = %v, want none", customer.Diagnostics)
+	}
+	for i, w := range want {
+		if got := withoutPositions(customer.Fields[i]); !reflect.DeepEqual(got, w) {
+			t.Errorf("field %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestParseEmbedTag(t *testing.T) {
+	tests := []struct {
+		tag        string
+		wantEmbed  bool
+		wantPrefix string
+	}{
+		{"", false, ""},
+		{"embed", true, ""},
+		{"embed,prefix:addr_", true, "addr_"},
+		{"prefix:addr_", false, "addr_"},
+		{"pk", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			embed, prefix := parseEmbedTag(tt.tag)
+			if embed != tt.wantEmbed || prefix != tt.wantPrefix {
+				t.Errorf("parseEmbedTag(%q) = (%v, %q), want (%v, %q)", tt.tag, embed, prefix, tt.wantEmbed, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestParseMultiNameFields(t *testing.T) {
+	p := New()
+
+	if err := p.ParseFiles([]string{"../../test/fixtures/multi_name.go"}); err != nil {
+		t.Fatalf("ParseFiles() error = %v", err)
+	}
+
+	structs := p.GetStructs()["fixtures"]
+	if len(structs) != 1 {
+		t.Fatalf("got %d structs, want 1", len(structs))
+	}
+
+	want := []Field{
+		{Name: "ID", Type: "int64", DatabaseTag: "pk"},
+		{Name: "Lat", Type: "float64", DatabaseTag: "index:idx_coords"},
+		{Name: "Lng", Type: "float64", DatabaseTag: "index:idx_coords"},
+		{Name: "Label", Type: "string"},
+	}
+
+	fields := structs[0].Fields
+	if len(fields) != len(want) {
+		t.Fatalf("got %d fields, want %d: %+v", len(fields), len(want), fields)
+	}
+	for i, w := range want {
+		if got := withoutPositions(fields[i]); !reflect.DeepEqual(got, w) {
+			t.Errorf("field %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestParseStructDirectives(t *testing.T) {
+	p := New()
+
+	if err := p.ParseFiles([]string{"../../test/fixtures/directives.go"}); err != nil {
+		t.Fatalf("ParseFiles() error = %v", err)
+	}
+
+	structs := p.GetStructs()["fixtures"]
+	if len(structs) != 1 {
+		t.Fatalf("got %d structs, want 1", len(structs))
+	}
+
+	want := []struct {
+		Directive
+		line int
+	}{
+		{Directive{Key: "table", Value: "orders"}, 5},
+		{Directive{Key: "schema", Value: "shop"}, 5},
+		{Directive{Key: "unlogged"}, 5},
+		{Directive{Key: "with", Value: "fillfactor=70"}, 6},
+	}
+	got := structs[0].Directives
+	if len(got) != len(want) {
+		t.Fatalf("got %d directives, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].Key != w.Key || got[i].Value != w.Value {
+			t.Errorf("directive %d = %+v, want %+v", i, got[i], w.Directive)
+		}
+		if got[i].Pos.Line != w.line || !strings.HasSuffix(got[i].Pos.Filename, "directives.go") {
+			t.Errorf("directive %d Pos = %v, want directives.go:%d", i, got[i].Pos, w.line)
+		}
+	}
+}
+
+func TestSplitDirectiveTokens(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"table=orders schema=shop", []string{"table=orders", "schema=shop"}},
+		{"  unlogged  ", []string{"unlogged"}},
+		{`check="start_at < end_at" unlogged`, []string{`check="start_at < end_at"`, "unlogged"}},
+		{`check="name <> \"x y\""`, []string{`check="name <> \"x y\""`}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := splitDirectiveTokens(tt.line)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("splitDirectiveTokens(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+// withoutPositions clears the source positions of f so that fields can be
+// compared against literals.
+func withoutPositions(f Field) Field {
+	f.Pos = token.Position{}
+	f.TagPos = token.Position{}
+	return f
+}
+
+func TestParseFieldPositions(t *testing.T) {
+	src := "package models\n\ntype User struct {\n\tID   int64  `json:\"id\" db:\"pk\"`\n\tName string\n}\n"
+
+	p := New()
+	if err := p.ParseSource("user.go", []byte(src)); err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	user := findParsedStruct(p, "User")
+	if user == nil {
+		t.Fatal("User struct not found")
+	}
+
+	tests := []struct {
+		field  string
+		pos    string
+		tagPos string
+	}{
+		{"ID", "user.go:4:2", "user.go:4:29"},
+		{"Name", "user.go:5:2", "-"},
+	}
+	for i, tt := range tests {
+		f := user.Fields[i]
+		if f.Name != tt.field {
+			t.Fatalf("field %d = %s, want %s", i, f.Name, tt.field)
+		}
+		if got := f.Pos.String(); got != tt.pos {
+			t.Errorf("%s Pos = %s, want %s", f.Name, got, tt.pos)
+		}
+		if got := f.TagPos.String(); got != tt.tagPos {
+			t.Errorf("%s TagPos = %s, want %s", f.Name, got, tt.tagPos)
+		}
+	}
+}
+
+func TestParseEmbeddedColumnOverride(t *testing.T) {
+	src := "package models\n\ntype Address struct {\n\tStreet string `db:\"column:str\"`\n}\n\ntype Person struct {\n\tID   int64   `db:\"pk\"`\n\tHome Address `db:\"embed,prefix:home_\"`\n}\n"
+
+	p := New()
+	if err := p.ParseSource("person.go", []byte(src)); err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	person := findParsedStruct(p, "Person")
+	if person == nil || len(person.Fields) != 2 {
+		t.Fatalf("Person fields = %+v, want 2", person)
+	}
+	if got := person.Fields[1].Column; got != "home_str" {
+		t.Errorf("Column = %q, want home_str", got)
+	}
+}
+
+func TestParseEmbedDiagnostics(t *testing.T) {
+	src := "package models\n\n" +
+		"import \"example.com/audit\"\n\n" +
+		"type Named interface{ Title() string }\n\n" +
+		"type Code = string\n\n" +
+		"type Label string\n\n" +
+		"type Inner struct {\n" +
+		"\tKind Label `db:\"embed\"`\n" +
+		"}\n\n" +
+		"type Node struct {\n" +
+		"\t*Node\n" +
+		"\tName string\n" +
+		"}\n\n" +
+		"type Account struct {\n" +
+		"\taudit.Stamps\n" +
+		"\t*audit.Owner\n" +
+		"\tNamed\n" +
+		"\tCode\n" +
+		"\tLabel\n" +
+		"\tInner\n" +
+		"\tID   int64          `db:\"pk\"`\n" +
+		"\tHome audit.Address `db:\"embed\"`\n" +
+		"}\n"
+
+	p := New()
+	if err := p.ParseSource("account.go", []byte(src)); err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		want  []string
+		field []string
+	}{
+		{
+			name: "Account",
+			want: []string{
+				"account.go:21:2: embedded type audit.Stamps could not be resolved, so its fields are not inlined",
+				"account.go:22:2: embedded type *audit.Owner could not be resolved, so its fields are not inlined",
+				"account.go:23:2: embedded type Named is not a struct, so it is not inlined",
+				"account.go:24:2: embedded type Code is not a struct, so it is not inlined",
+				"account.go:25:2: embedded type Label is not a struct, so it is not inlined",
+				"account.go:12:2: embedded type Label is not a struct, so it is not inlined",
+				"account.go:28:7: embedded type audit.Address could not be resolved, so its fields are not inlined",
+			},
+			// Fields tagged embed that cannot be inlined stay columns
+			field: []string{"Kind", "ID", "Home"},
+		},
+		{
+			name: "Node",
+			want: []string{"account.go:16:3: embedded type Node is nested more than 8 levels deep, so its fields are not inlined"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := findParsedStruct(p, tt.name)
+			if s == nil {
+				t.Fatalf("%s struct not found", tt.name)
+			}
+			var got []string
+			for _, d := range s.Diagnostics {
+				got = append(got, d.Pos.String()+": "+d.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diagnostics = %q, want %q", got, tt.want)
+			}
+			if tt.field == nil {
+				return
+			}
+			var fields []string
+			for _, f := range s.Fields {
+				fields = append(fields, f.Name)
+			}
+			if !reflect.DeepEqual(fields, tt.field) {
+				t.Errorf("Fields = %v, want %v", fields, tt.field)
+			}
+		})
+	}
+}
+
+func TestParseDropsEmbeddedStructs(t *testing.T) {
+	src := "package models\n\n" +
+		"type BaseModel struct {\n\tID int64 `db:\"pk\"`\n}\n\n" +
+		"type Address struct {\n\tStreet string\n}\n\n" +
+		"//structify:table=tags\n" +
+		"type Tag struct {\n\tName string `db:\"pk\"`\n}\n\n" +
+		"type Customer struct {\n\tBaseModel\n\tHome Address `db:\"embed\"`\n}\n\n" +
+		"type Post struct {\n\t*BaseModel\n\tTag\n}\n"
+
+	p := New()
+	if err := p.ParseSource("models.go", []byte(src)); err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+
+	var got []string
+	for _, s := range p.GetStructs()["models"] {
+		got = append(got, s.Name)
+	}
+	// Tag is embedded too, but its table= directive keeps its table
+	want := []string{"Tag", "Customer", "Post"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetStructs() = %v, want %v", got, want)
+	}
+}
+
+func TestParseGenericFieldType(t *testing.T) {
+	src := "package models\n\nimport \"database/sql\"\n\ntype Profile struct {\n\tID       int64             `db:\"pk\"`\n\tNickname sql.Null[string]\n\tPair     Pair[int, string]\n}\n\ntype Pair[K, V any] struct {\n\tKey K\n\tVal V\n}\n"
+
+	p := New()
+	if err := p.ParseSource("profile.go", []byte(src)); err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	profile := findParsedStruct(p, "Profile")
+	if profile == nil || len(profile.Fields) != 3 {
+		t.Fatalf("Profile fields = %+v, want 3", profile)
+	}
+	if got := profile.Fields[1].Type; got != "sql.Null[string]" {
+		t.Errorf("Nickname Type = %q, want sql.Null[string]", got)
+	}
+	if got := profile.Fields[2].Type; got != "Pair[int, string]" {
+		t.Errorf("Pair Type = %q, want Pair[int, string]", got)
+	}
+}
+
+func TestParseDocComments(t *testing.T) {
+	src := "package models\n\n" +
+		"// Address is a postal address.\n" +
+		"type Address struct {\n" +
+		"\t// Street includes the house number.\n" +
+		"\tStreet string\n" +
+		"}\n\n" +
+		"// Customer is somebody who buys things.\n" +
+		"//\n" +
+		"//structify:table=customers\n" +
+		"type Customer struct {\n" +
+		"\t// ID is the customer's number.\n" +
+		"\tID   int64   `db:\"pk\"`\n" +
+		"\tName string  // display name\n" +
+		"\tHome Address `db:\"embed\"`\n" +
+		"\tNote string\n" +
+		"}\n"
+
+	p := New()
+	if err := p.ParseSource("customer.go", []byte(src)); err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	customer := findParsedStruct(p, "Customer")
+	if customer == nil {
+		t.Fatal("Customer struct not found")
+	}
+	if want := "Customer is somebody who buys things."; customer.Doc != want {
+		t.Errorf("Doc = %q, want %q", customer.Doc, want)
+	}
+
+	tests := []struct {
+		field string
+		doc   string
+	}{
+		{"ID", "ID is the customer's number."},
+		{"Name", "display name"},
+		{"HomeStreet", "Street includes the house number."},
+		{"Note", ""},
+	}
+	for i, tt := range tests {
+		f := customer.Fields[i]
+		if f.Name != tt.field {
+			t.Fatalf("field %d = %s, want %s", i, f.Name, tt.field)
+		}
+		if f.Doc != tt.doc {
+			t.Errorf("%s Doc = %q, want %q", f.Name, f.Doc, tt.doc)
+		}
+	}
+}
+
+func TestParsePackageSchema(t *testing.T) {
+	src := "//structify:schema=billing\n" +
+		"package models\n\n" +
+		"type Invoice struct {\n" +
+		"\tID int64 `db:\"pk\"`\n" +
+		"}\n"
+
+	p := New()
+	if err := p.ParseSource("invoice.go", []byte(src)); err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	invoice := findParsedStruct(p, "Invoice")
+	if invoice == nil {
+		t.Fatal("Invoice struct not found")
+	}
+	if invoice.PackageSchema != "billing" {
+		t.Errorf("PackageSchema = %q, want billing", invoice.PackageSchema)
+	}
+}
+
+func TestParseViewSQL(t *testing.T) {
+	src := "package models\n\n" +
+		"// ActiveUser lists users that logged in recently.\n" +
+		"//\n" +
+		"//structify:view\n" +
+		"//sql:\"SELECT id, name FROM users\"\n" +
+		"//sql:\"WHERE last_login > now() - interval '30 days'\"\n" +
+		"type ActiveUser struct {\n" +
+		"\tID   int64\n" +
+		"\tName string\n" +
+		"}\n"
+
+	p := New()
+	if err := p.ParseSource("active_user.go", []byte(src)); err != nil {
+		t.Fatalf("ParseSource() error = %v", err)
+	}
+	view := findParsedStruct(p, "ActiveUser")
+	if view == nil {
+		t.Fatal("ActiveUser struct not found")
+	}
+	want := "SELECT id, name FROM users\nWHERE last_login > now() - interval '30 days'"
+	if view.SQL != want {
+		t.Errorf("SQL = %q, want %q", view.SQL, want)
+	}
+	if want := "ActiveUser lists users that logged in recently."; view.Doc != want {
+		t.Errorf("Doc = %q, want %q", view.Doc, want)
+	}
+}
+
+func TestParseViewSQLFile(t *testing.T) {
+	dir := t.TempDir()
+	src := "package models\n\n" +
+		"//structify:view=materialized sql_file=views/sales.sql\n" +
+		"type DailySales struct {\n" +
+		"\tDay   string\n" +
+		"\tTotal int64\n" +
+		"}\n"
+	if err := os.MkdirAll(filepath.Join(dir, "views"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "views", "sales.sql"), []byte("SELECT day, sum(total) AS total FROM orders GROUP BY day;\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	goFile := filepath.Join(dir, "sales.go")
+	if err := os.WriteFile(goFile, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := New()
+	if err := p.ParseFiles([]string{goFile}); err != nil {
+		t.Fatalf("ParseFiles() error = %v", err)
+	}
+	view := findParsedStruct(p, "DailySales")
+	if view == nil {
+		t.Fatal("DailySales struct not found")
+	}
+	if want := "SELECT day, sum(total) AS total FROM orders GROUP BY day;"; view.SQL != want {
+		t.Errorf("SQL = %q, want %q", view.SQL, want)
+	}
+
+	fsys := fstest.MapFS{
+		"models/sales.go":        {Data: []byte(src)},
+		"models/views/sales.sql": {Data: []byte("SELECT 1")},
+	}
+	p = New()
+	if err := p.ParseFS(fsys, []string{"models/sales.go"}); err != nil {
+		t.Fatalf("ParseFS() error = %v", err)
+	}
+	if view := findParsedStruct(p, "DailySales"); view == nil || view.SQL != "SELECT 1" {
+		t.Errorf("ParseFS() view = %+v, want SQL read from fsys", view)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "views", "sales.sql")); err != nil {
+		t.Fatal(err)
+	}
+	if err := New().ParseFiles([]string{goFile}); err == nil {
+		t.Error("ParseFiles() error = nil, want error for missing sql_file")
+	}
+	if err := New().ParseSource("sales.go", []byte(src)); err == nil {
+		t.Error("ParseSource() error = nil, want error for sql_file of in-memory source")
+	}
+}
@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/n0xum/structify/internal/application/query"
 )
 
 func TestNew(t *testing.T) {
@@ -70,6 +74,29 @@ func TestAppRunToSQLWithOutput(t *testing.T) {
 	}
 }
 
+func TestAppRunToSQLReset(t *testing.T) {
+	fixture := "../../test/fixtures/user.go"
+	if _, err := os.Stat(fixture); os.IsNotExist(err) {
+		t.Skip("fixture not found")
+	}
+
+	tmp := filepath.Join(t.TempDir(), "reset.sql")
+	app := New("1.0.0")
+	if err := app.Run([]string{"structify", "--to-sql", "--reset", "--output", tmp, fixture}); err != nil {
+		t.Fatalf("Run() --to-sql --reset error = %v", err)
+	}
+	data, err := os.ReadFile(tmp)
+	if err != nil {
+		t.Fatalf("reading output: %v", err)
+	}
+	out := string(data)
+	drop := strings.Index(out, `DROP TABLE IF EXISTS "user" CASCADE;`)
+	create := strings.Index(out, `CREATE TABLE "user" (`)
+	if drop == -1 || create == -1 || drop > create {
+		t.Errorf("Run() --reset output should drop before it creates:\n%s", out)
+	}
+}
+
 func TestAppRunToRepoMissingModel(t *testing.T) {
 	app := New("1.0.0")
 	err := app.Run([]string{"structify", "--to-repo", "--interface", "repo.go"})
@@ -221,3 +248,32 @@ func TestAppRunInvalidFlag(t *testing.T) {
 		t.Error("Run() with invalid flag should return error")
 	}
 }
+
+func TestAppRunToSQLMalformedTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.go")
+	src := "package test\n\ntype Order struct {\n\tID     int64 `db:\"pk\"`\n\tUserID int64 `db:\"fk:user\"`\n}\n"
+	if err := os.WriteFile(path, []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(t.TempDir(), "out.sql")
+	app := New("1.0.0")
+	err := app.Run([]string{"structify", "--to-sql", "--output", out, path})
+	if err == nil {
+		t.Fatal("Run() --to-sql with malformed db tags should return error")
+	}
+	if _, statErr := os.Stat(out); !os.IsNotExist(statErr) {
+		t.Error("output file should not be written when tags have errors")
+	}
+}
+
+func TestFixturesHaveNoDiagnostics(t *testing.T) {
+	app := New("1.0.0")
+	result, err := app.queryHandler.Parse(context.Background(), &query.ParseQuery{Packages: []string{"../../test/fixtures"}})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	for _, d := range result.Diagnostics {
+		t.Errorf("unexpected diagnostic: %s", d)
+	}
+}
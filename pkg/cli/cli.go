@@ -10,12 +10,16 @@ import (
 	"github.com/n0xum/structify/internal/application"
 	"github.com/n0xum/structify/internal/application/command"
 	"github.com/n0xum/structify/internal/application/query"
+	"github.com/n0xum/structify/internal/domain/entity"
 	"github.com/n0xum/structify/internal/generator"
 )
 
 type Command struct {
 	FS            *flag.FlagSet
 	ToSQL         bool
+	IfNotExists   bool
+	Drop          bool
+	Reset         bool
 	ToRepo        bool
 	ModelFile     string
 	InterfaceFile string
@@ -30,6 +34,9 @@ func NewCommand() *Command {
 
 	cmd.FS.BoolVar(&cmd.ToSQL, "to-sql", false, "Generate PostgreSQL CREATE TABLE statements")
 	cmd.FS.BoolVar(&cmd.ToSQL, "to-schema", false, "Generate PostgreSQL CREATE TABLE statements (alias)")
+	cmd.FS.BoolVar(&cmd.IfNotExists, "if-not-exists", false, "Make the schema safe to re-run (for --to-sql)")
+	cmd.FS.BoolVar(&cmd.Drop, "drop", false, "Generate DROP TABLE IF EXISTS statements instead (for --to-sql)")
+	cmd.FS.BoolVar(&cmd.Reset, "reset", false, "Generate DROP statements followed by the schema (for --to-sql)")
 	cmd.FS.BoolVar(&cmd.ToRepo, "to-repo", false, "Generate repository implementation from interface")
 	cmd.FS.StringVar(&cmd.ModelFile, "model", "", "Model Go file with struct definitions (for --to-repo)")
 	cmd.FS.StringVar(&cmd.InterfaceFile, "interface", "", "Go file containing the repository interface (for --to-repo)")
@@ -46,6 +53,22 @@ func (c *Command) Parse(args []string) error {
 }
 
 func (c *Command) Validate() error {
+	schemaFlags := []struct {
+		name string
+		set  bool
+	}{
+		{"--if-not-exists", c.IfNotExists},
+		{"--drop", c.Drop},
+		{"--reset", c.Reset},
+	}
+	for _, flag := range schemaFlags {
+		if flag.set && !c.ToSQL {
+			return fmt.Errorf("%s requires --to-sql", flag.name)
+		}
+	}
+	if c.Drop && c.Reset {
+		return fmt.Errorf("--drop and --reset cannot be combined")
+	}
 	if c.ToRepo {
 		if c.ModelFile == "" {
 			return fmt.Errorf("--to-repo requires --model")
@@ -98,6 +121,10 @@ func (a *App) Run(args []string) error {
 		return fmt.Errorf("no arguments specified")
 	}
 
+	if args[1] == "diff" {
+		return a.runDiff(context.Background(), args[2:])
+	}
+
 	if err := a.cmd.Parse(args); err != nil {
 		return err
 	}
@@ -118,20 +145,28 @@ func (a *App) Run(args []string) error {
 	}
 
 	// Standard struct-based flow (--to-sql, or parse-only)
-	inputFiles := a.cmd.FS.Args()
+	inputFiles, inputPackages := splitInputs(a.cmd.FS.Args())
 
-	parseQuery := &query.ParseQuery{Files: inputFiles}
+	parseQuery := &query.ParseQuery{Files: inputFiles, Packages: inputPackages}
 	parseResult, err := a.queryHandler.Parse(ctx, parseQuery)
 	if err != nil {
 		return err
 	}
+	if err := reportDiagnostics(parseResult.Diagnostics); err != nil {
+		return err
+	}
 
 	var output string
 	if a.cmd.ToSQL {
 		if parseResult.Count == 0 {
 			return fmt.Errorf("no structs found")
 		}
-		cmd := &command.GenerateSchemaCommand{Entities: parseResult.EntityList}
+		cmd := &command.GenerateSchemaCommand{
+			Entities:    parseResult.EntityList,
+			IfNotExists: a.cmd.IfNotExists,
+			Drop:        a.cmd.Drop,
+			Reset:       a.cmd.Reset,
+		}
 		output, err = a.cmdHandler.GenerateSchema(ctx, cmd)
 		if err != nil {
 			return err
@@ -149,11 +184,15 @@ func (a *App) Run(args []string) error {
 
 func (a *App) runRepoGeneration(ctx context.Context) error {
 	// 1. Parse model files → entities
-	parseQuery := &query.ParseQuery{Files: []string{a.cmd.ModelFile}}
+	modelFiles, modelPackages := splitInputs([]string{a.cmd.ModelFile})
+	parseQuery := &query.ParseQuery{Files: modelFiles, Packages: modelPackages}
 	parseResult, err := a.queryHandler.Parse(ctx, parseQuery)
 	if err != nil {
 		return fmt.Errorf("parse model: %w", err)
 	}
+	if err := reportDiagnostics(parseResult.Diagnostics); err != nil {
+		return err
+	}
 	if parseResult.Count == 0 {
 		return fmt.Errorf("no structs found in model file %s", a.cmd.ModelFile)
 	}
@@ -198,6 +237,35 @@ func (a *App) runRepoGeneration(ctx context.Context) error {
 	return a.writeOutput(output, outputFile)
 }
 
+// reportDiagnostics prints diagnostics to stderr like compiler messages and
+// returns an error if any of them is an error.
+func reportDiagnostics(diagnostics []entity.Diagnostic) error {
+	errCount := 0
+	for _, d := range diagnostics {
+		fmt.Fprintln(os.Stderr, d)
+		if d.Severity == entity.SeverityError {
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		return fmt.Errorf("%d error(s) in struct tags", errCount)
+	}
+	return nil
+}
+
+// splitInputs separates Go source files from package patterns such as
+// "./models" or "./models/...", which are loaded with full type information.
+func splitInputs(args []string) (files []string, packages []string) {
+	for _, arg := range args {
+		if strings.HasSuffix(arg, ".go") {
+			files = append(files, arg)
+		} else {
+			packages = append(packages, arg)
+		}
+	}
+	return files, packages
+}
+
 func (a *App) writeOutput(output string, outputFile string) error {
 	if outputFile != "" {
 		return os.WriteFile(outputFile, []byte(output), 0600)
@@ -235,11 +303,18 @@ func (a *App) printUsage() {
 	fmt.Fprintln(os.Stderr, "structify - Go struct to PostgreSQL schema converter")
 	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "Usage:")
-	fmt.Fprintln(os.Stderr, "  structify [flags] <input-files...>")
+	fmt.Fprintln(os.Stderr, "  structify [flags] <input-files or packages...>")
+	fmt.Fprintln(os.Stderr, "  structify diff --from <old model> --to <new model> [--snapshot <file>] [-o <file>]")
 	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "Flags:")
 	fmt.Fprintln(os.Stderr, "  --to-sql, --to-schema")
 	fmt.Fprintln(os.Stderr, "        Generate PostgreSQL CREATE TABLE statements")
+	fmt.Fprintln(os.Stderr, "  --if-not-exists")
+	fmt.Fprintln(os.Stderr, "        With --to-sql, make the schema safe to apply more than once")
+	fmt.Fprintln(os.Stderr, "  --drop")
+	fmt.Fprintln(os.Stderr, "        With --to-sql, generate DROP TABLE IF EXISTS ... CASCADE statements instead")
+	fmt.Fprintln(os.Stderr, "  --reset")
+	fmt.Fprintln(os.Stderr, "        With --to-sql, drop and recreate everything (for local development)")
 	fmt.Fprintln(os.Stderr, "  --to-repo --model <file> --interface <file>")
 	fmt.Fprintln(os.Stderr, "        Generate repository implementation from interface")
 	fmt.Fprintln(os.Stderr, "  --output, -o <file>")
@@ -249,8 +324,17 @@ func (a *App) printUsage() {
 	fmt.Fprintln(os.Stderr, "  --help")
 	fmt.Fprintln(os.Stderr, "        Show this help")
 	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Diff flags:")
+	fmt.Fprintln(os.Stderr, "  --from, --to <snapshot.json or files and packages, comma-separated>")
+	fmt.Fprintln(os.Stderr, "        The model the database has and the model it should get")
+	fmt.Fprintln(os.Stderr, "  --snapshot <file>")
+	fmt.Fprintln(os.Stderr, "        Save the --to model as a snapshot to diff against next time")
+	fmt.Fprintln(os.Stderr, "")
 	fmt.Fprintln(os.Stderr, "Examples:")
 	fmt.Fprintln(os.Stderr, "  structify --to-sql ./models/user.go")
+	fmt.Fprintln(os.Stderr, "  structify --to-sql ./models/...")
+	fmt.Fprintln(os.Stderr, "  structify --to-sql --reset ./models/... -o reset.sql")
+	fmt.Fprintln(os.Stderr, "  structify diff --from schema.json --to ./models/... --snapshot schema.json -o migration.sql")
 	fmt.Fprintln(os.Stderr, "  structify --to-repo --model ./models/user.go --interface ./repo/user_repo.go")
 	fmt.Fprintln(os.Stderr, "  structify --to-repo --model ./models/user.go --interface ./repo/user_repo.go -o ./repo/user_repo.gen.go")
 	fmt.Fprintln(os.Stderr, "")
@@ -47,6 +47,9 @@ func TestCommandValidate(t *testing.T) {
 		toRepo        bool
 		modelFile     string
 		interfaceFile string
+		ifNotExists   bool
+		drop          bool
+		reset         bool
 		wantErr       bool
 	}{
 		{
@@ -76,6 +79,30 @@ func TestCommandValidate(t *testing.T) {
 			interfaceFile: "repo.go",
 			wantErr:       false,
 		},
+		{
+			name:        "to SQL if not exists",
+			toSQL:       true,
+			ifNotExists: true,
+			wantErr:     false,
+		},
+		{
+			name:    "to SQL reset",
+			toSQL:   true,
+			reset:   true,
+			wantErr: false,
+		},
+		{
+			name:    "drop without to SQL",
+			drop:    true,
+			wantErr: true,
+		},
+		{
+			name:    "drop and reset",
+			toSQL:   true,
+			drop:    true,
+			reset:   true,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -85,6 +112,9 @@ func TestCommandValidate(t *testing.T) {
 			cmd.ToRepo = tt.toRepo
 			cmd.ModelFile = tt.modelFile
 			cmd.InterfaceFile = tt.interfaceFile
+			cmd.IfNotExists = tt.ifNotExists
+			cmd.Drop = tt.drop
+			cmd.Reset = tt.reset
 
 			err := cmd.Validate()
 			if tt.wantErr && err == nil {
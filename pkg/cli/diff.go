@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/n0xum/structify/internal/application"
+	"github.com/n0xum/structify/internal/application/command"
+	"github.com/n0xum/structify/internal/application/query"
+	"github.com/n0xum/structify/internal/domain/entity"
+)
+
+// DiffCommand holds the flags of "structify diff". From and To each name a
+// snapshot (.json) or comma-separated Go files and package patterns.
+type DiffCommand struct {
+	FS         *flag.FlagSet
+	From       string
+	To         string
+	Snapshot   string
+	OutputFile string
+}
+
+func NewDiffCommand() *DiffCommand {
+	cmd := &DiffCommand{
+		FS: flag.NewFlagSet("structify diff", flag.ContinueOnError),
+	}
+
+	cmd.FS.StringVar(&cmd.From, "from", "", "Old model: snapshot (.json), Go files or packages")
+	cmd.FS.StringVar(&cmd.To, "to", "", "New model: snapshot (.json), Go files or packages")
+	cmd.FS.StringVar(&cmd.Snapshot, "snapshot", "", "Save the new model as a snapshot for the next diff")
+	cmd.FS.StringVar(&cmd.OutputFile, "o", "", "Output file")
+	cmd.FS.StringVar(&cmd.OutputFile, "output", "", "Output file")
+
+	return cmd
+}
+
+// Parse parses the arguments following "diff".
+func (c *DiffCommand) Parse(args []string) error {
+	return c.FS.Parse(args)
+}
+
+func (c *DiffCommand) Validate() error {
+	if c.From == "" {
+		return fmt.Errorf("diff requires --from")
+	}
+	if c.To == "" {
+		return fmt.Errorf("diff requires --to")
+	}
+	if c.FS.NArg() > 0 {
+		return fmt.Errorf("diff takes no arguments, got %q", c.FS.Arg(0))
+	}
+	return nil
+}
+
+// runDiff writes the migration from the --from model to the --to model,
+// with the up and down steps under "-- migrate:up" and "-- migrate:down".
+// Destructive up steps and changes it cannot migrate are also reported on
+// stderr.
+func (a *App) runDiff(ctx context.Context, args []string) error {
+	diffCmd := NewDiffCommand()
+	if err := diffCmd.Parse(args); err != nil {
+		return err
+	}
+	if err := diffCmd.Validate(); err != nil {
+		return err
+	}
+
+	from, err := a.loadModel(ctx, diffCmd.From)
+	if err != nil {
+		return fmt.Errorf("--from: %w", err)
+	}
+	to, err := a.loadModel(ctx, diffCmd.To)
+	if err != nil {
+		return fmt.Errorf("--to: %w", err)
+	}
+
+	migration, err := a.cmdHandler.Diff(ctx, &command.DiffCommand{From: from, To: to})
+	if err != nil {
+		return err
+	}
+
+	if diffCmd.Snapshot != "" {
+		var buf bytes.Buffer
+		if err := application.WriteSnapshot(&buf, to); err != nil {
+			return err
+		}
+		if err := os.WriteFile(diffCmd.Snapshot, buf.Bytes(), 0600); err != nil {
+			return err
+		}
+	}
+
+	if !migration.HasChanges() {
+		fmt.Fprintln(os.Stderr, "No schema changes")
+		return nil
+	}
+
+	destructive := 0
+	for _, step := range migration.Up {
+		switch {
+		case step.Destructive:
+			fmt.Fprintf(os.Stderr, "warning: destructive: %s\n", step.Warning)
+			destructive++
+		case step.SQL == "":
+			fmt.Fprintf(os.Stderr, "warning: not migrated: %s\n", step.Warning)
+		}
+	}
+	if destructive > 0 {
+		fmt.Fprintf(os.Stderr, "%d destructive step(s); review the migration before applying it\n", destructive)
+	}
+
+	output := "-- migrate:up\n" + migration.UpSQL() + "-- migrate:down\n" + migration.DownSQL()
+	return a.writeOutput(output, diffCmd.OutputFile)
+}
+
+// loadModel reads the entities of a snapshot file, or parses the
+// comma-separated Go files and packages in source.
+func (a *App) loadModel(ctx context.Context, source string) ([]*entity.Entity, error) {
+	if strings.HasSuffix(source, ".json") {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return application.ReadSnapshot(f)
+	}
+
+	files, packages := splitInputs(strings.Split(source, ","))
+	parseResult, err := a.queryHandler.Parse(ctx, &query.ParseQuery{Files: files, Packages: packages})
+	if err != nil {
+		return nil, err
+	}
+	if err := reportDiagnostics(parseResult.Diagnostics); err != nil {
+		return nil, err
+	}
+	if parseResult.Count == 0 {
+		return nil, fmt.Errorf("no structs found in %s", source)
+	}
+	return parseResult.EntityList, nil
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffCommandValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "from and to", args: []string{"--from", "old.json", "--to", "./models"}},
+		{name: "missing from", args: []string{"--to", "./models"}, wantErr: "diff requires --from"},
+		{name: "missing to", args: []string{"--from", "old.json"}, wantErr: "diff requires --to"},
+		{name: "stray argument", args: []string{"--from", "old.json", "--to", "./models", "extra.go"}, wantErr: `diff takes no arguments, got "extra.go"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewDiffCommand()
+			if err := cmd.Parse(tt.args); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			err := cmd.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAppRunDiff(t *testing.T) {
+	dir := t.TempDir()
+	oldModel := filepath.Join(dir, "old.go")
+	newModel := filepath.Join(dir, "new.go")
+	oldSrc := "package models\n\ntype Account struct {\n\tID    int64 `db:\"pk\"`\n\tEmail string\n\tFax   *string\n}\n"
+	newSrc := "package models\n\ntype Account struct {\n\tID    int64  `db:\"pk\"`\n\tEmail string `db:\"unique\"`\n\tPlan  *string\n}\n"
+	if err := os.WriteFile(oldModel, []byte(oldSrc), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(newModel, []byte(newSrc), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "migration.sql")
+	snapshot := filepath.Join(dir, "schema.json")
+	app := New("1.0.0")
+	err := app.Run([]string{"structify", "diff", "--from", oldModel, "--to", newModel, "--snapshot", snapshot, "-o", out})
+	if err != nil {
+		t.Fatalf("Run() diff error = %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	up, down, ok := strings.Cut(string(data), "-- migrate:down\n")
+	if !ok || !strings.HasPrefix(up, "-- migrate:up\n") {
+		t.Fatalf("Run() diff output missing migrate sections:\n%s", data)
+	}
+	for _, want := range []string{
+		`ALTER TABLE "account" ADD COLUMN "plan" VARCHAR(255);`,
+		`ALTER TABLE "account" ADD CONSTRAINT "uq_account_email" UNIQUE ("email");`,
+		"-- DESTRUCTIVE: drops column account.fax and its data\nALTER TABLE \"account\" DROP COLUMN \"fax\";",
+	} {
+		if !strings.Contains(up, want) {
+			t.Errorf("up migration missing %q:\n%s", want, up)
+		}
+	}
+	if !strings.Contains(down, `ALTER TABLE "account" ADD COLUMN "fax" VARCHAR(255);`) {
+		t.Errorf("down migration missing ADD COLUMN:\n%s", down)
+	}
+
+	// The snapshot stands in for the new model, so diffing against it
+	// finds nothing to do
+	unchanged := filepath.Join(dir, "unchanged.sql")
+	err = app.Run([]string{"structify", "diff", "--from", snapshot, "--to", newModel, "-o", unchanged})
+	if err != nil {
+		t.Fatalf("Run() diff from snapshot error = %v", err)
+	}
+	if _, err := os.Stat(unchanged); !os.IsNotExist(err) {
+		t.Error("output file should not be written when nothing changed")
+	}
+}
+
+func TestAppRunDiffErrors(t *testing.T) {
+	fixture := "../../test/fixtures/user.go"
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing to", args: []string{"structify", "diff", "--from", fixture}},
+		{name: "missing snapshot", args: []string{"structify", "diff", "--from", filepath.Join(t.TempDir(), "none.json"), "--to", fixture}},
+		{name: "no structs", args: []string{"structify", "diff", "--from", t.TempDir(), "--to", fixture}},
+	}
+
+	app := New("1.0.0")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := app.Run(tt.args); err == nil {
+				t.Errorf("Run() %v should return error", tt.args[1:])
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+CREATE TABLE "composite_pk_order_item" (
+    "order_id" BIGINT NOT NULL,
+    "item_id" BIGINT NOT NULL,
+    "quantity" INTEGER NOT NULL CONSTRAINT "ck_composite_pk_order_item_quantity" CHECK (quantity > 0),
+    "price" DOUBLE PRECISION NOT NULL,
+    CONSTRAINT "pk_composite_pk_order_item" PRIMARY KEY ("order_id", "item_id")
+);
+
+COMMENT ON TABLE "composite_pk_order_item" IS 'OrderItem with composite primary key (order_id, item_id)';
+
+CREATE TABLE "composite_unique_user" (
+    "id" BIGINT CONSTRAINT "pk_composite_unique_user" PRIMARY KEY,
+    "tenant_id" BIGINT NOT NULL,
+    "username" VARCHAR(255) NOT NULL,
+    "role" VARCHAR(255) NOT NULL,
+    "email" VARCHAR(255) NOT NULL CONSTRAINT "uq_composite_unique_user_email" UNIQUE,
+    CONSTRAINT "uq_tenant_role" UNIQUE ("tenant_id", "username", "role")
+);
+
+COMMENT ON TABLE "composite_unique_user" IS 'UserRole with composite unique constraint (tenant_id, role)';
+
+CREATE TABLE "composite_unique_address" (
+    "id" BIGINT CONSTRAINT "pk_composite_unique_address" PRIMARY KEY,
+    "user_id" BIGINT NOT NULL,
+    "address_type" VARCHAR(255) NOT NULL,
+    "address" VARCHAR(255) NOT NULL,
+    "city" VARCHAR(255) NOT NULL,
+    "zip" VARCHAR(255) NOT NULL,
+    CONSTRAINT "uq_user_address" UNIQUE ("user_id", "address_type")
+);
+
+COMMENT ON TABLE "composite_unique_address" IS 'Address with composite unique constraint';
+
+CREATE TABLE "composite_pk_permission" (
+    "role_id" BIGINT NOT NULL,
+    "resource_id" BIGINT NOT NULL,
+    "permission" VARCHAR(255) NOT NULL CONSTRAINT "uq_composite_pk_permission_permission" UNIQUE,
+    "can_write" BOOLEAN NOT NULL DEFAULT false,
+    CONSTRAINT "pk_composite_pk_permission" PRIMARY KEY ("role_id", "resource_id")
+);
+
+COMMENT ON TABLE "composite_pk_permission" IS 'Permission with composite primary key';
+
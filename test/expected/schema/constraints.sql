@@ -0,0 +1,10 @@
+CREATE TABLE "person" (
+    "id" BIGINT CONSTRAINT "pk_person" PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL CONSTRAINT "ck_person_name" CHECK (length(name) > 0),
+    "age" INTEGER NOT NULL CONSTRAINT "ck_person_age" CHECK (age >= 18),
+    "email" VARCHAR(255) NOT NULL CONSTRAINT "ck_person_email" CHECK (email ~* '^[a-z0-9._%+-]+@[a-z0-9.-]+\\.[a-z]{2,}$'),
+    "active" BOOLEAN NOT NULL DEFAULT true,
+    "role" VARCHAR(255) NOT NULL DEFAULT 'user',
+    "created" BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
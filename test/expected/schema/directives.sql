@@ -0,0 +1,9 @@
+CREATE SCHEMA IF NOT EXISTS "shop";
+
+CREATE UNLOGGED TABLE "shop"."orders" (
+    "id" BIGINT CONSTRAINT "pk_orders" PRIMARY KEY,
+    "total" DOUBLE PRECISION NOT NULL
+) WITH (fillfactor=70);
+
+COMMENT ON TABLE "shop"."orders" IS 'ShopOrder is stored in the shop schema.';
+
@@ -0,0 +1,14 @@
+CREATE TABLE "customer" (
+    "id" BIGINT CONSTRAINT "pk_customer" PRIMARY KEY,
+    "created_at" TIMESTAMP NOT NULL,
+    "updated_at" TIMESTAMP NOT NULL,
+    "name" VARCHAR(255) NOT NULL,
+    "ship_street" VARCHAR(255) NOT NULL,
+    "ship_city" VARCHAR(255) NOT NULL,
+    "billing_street" VARCHAR(255) NOT NULL,
+    "billing_city" VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX "ship_city_idx" ON "customer" ("ship_city");
+CREATE INDEX "billing_city_idx" ON "customer" ("billing_city");
+
@@ -0,0 +1,11 @@
+CREATE TABLE "exotic_types" (
+    "map_field" TEXT,
+    "chan_field" TEXT,
+    "interface_field" TEXT,
+    "slice_field" TEXT[],
+    "pointer_field" INTEGER,
+    "nested_map" TEXT
+);
+
+COMMENT ON TABLE "exotic_types" IS 'ExoticTypes tests parsing of map, chan, interface{}, ellipsis, and array types.';
+
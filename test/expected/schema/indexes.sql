@@ -0,0 +1,27 @@
+CREATE TABLE "indexed_user" (
+    "id" BIGINT CONSTRAINT "pk_indexed_user" PRIMARY KEY,
+    "email" VARCHAR(255) NOT NULL,
+    "username" VARCHAR(255) NOT NULL,
+    "active" BOOLEAN NOT NULL DEFAULT true
+);
+
+CREATE UNIQUE INDEX "idx_email" ON "indexed_user" ("email");
+CREATE INDEX "username_idx" ON "indexed_user" ("username");
+
+CREATE TABLE "indexed_product" (
+    "id" BIGINT CONSTRAINT "pk_indexed_product" PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL,
+    "category" VARCHAR(255) NOT NULL,
+    "price" DOUBLE PRECISION NOT NULL CONSTRAINT "ck_indexed_product_price" CHECK (price >= 0),
+    "available" BOOLEAN NOT NULL DEFAULT true
+);
+
+CREATE INDEX "idx_name_category" ON "indexed_product" ("name", "category");
+
+CREATE TABLE "order_status" (
+    "id" BIGINT CONSTRAINT "pk_order_status" PRIMARY KEY,
+    "status" VARCHAR(255) NOT NULL CONSTRAINT "ck_order_status_status" CHECK ("status" IN ('pending', 'processing', 'shipped', 'delivered', 'cancelled')),
+    "priority" VARCHAR(255) NOT NULL CONSTRAINT "ck_order_status_priority" CHECK ("priority" IN ('low', 'medium', 'high')),
+    "created_at" BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
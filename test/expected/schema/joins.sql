@@ -0,0 +1,29 @@
+CREATE TABLE "join_user" (
+    "id" BIGINT CONSTRAINT "pk_join_user" PRIMARY KEY,
+    "username" VARCHAR(255) NOT NULL CONSTRAINT "uq_join_user_username" UNIQUE,
+    "email" VARCHAR(255) NOT NULL,
+    "active" BOOLEAN NOT NULL DEFAULT true
+);
+
+CREATE TABLE "join_product" (
+    "id" BIGINT CONSTRAINT "pk_join_product" PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL,
+    "price" DOUBLE PRECISION NOT NULL CONSTRAINT "ck_join_product_price" CHECK (price >= 0),
+    "description" VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE "join_order" (
+    "id" BIGINT CONSTRAINT "pk_join_order" PRIMARY KEY,
+    "user_id" BIGINT NOT NULL CONSTRAINT "fk_join_order_user_id" REFERENCES "join_user"("id") ON DELETE CASCADE,
+    "status" VARCHAR(255) NOT NULL CONSTRAINT "ck_join_order_status" CHECK ("status" IN ('pending', 'processing', 'shipped', 'delivered', 'cancelled')),
+    "created_at" BIGINT NOT NULL DEFAULT extract(epoch from now())
+);
+
+CREATE TABLE "join_order_item" (
+    "id" BIGINT CONSTRAINT "pk_join_order_item" PRIMARY KEY,
+    "order_id" BIGINT NOT NULL CONSTRAINT "fk_join_order_item_order_id" REFERENCES "join_order"("id") ON DELETE CASCADE,
+    "product_id" BIGINT NOT NULL CONSTRAINT "fk_join_order_item_product_id" REFERENCES "join_product"("id") ON DELETE SET NULL,
+    "quantity" INTEGER NOT NULL CONSTRAINT "ck_join_order_item_quantity" CHECK (quantity > 0) DEFAULT 1,
+    "price" DOUBLE PRECISION NOT NULL
+);
+
@@ -0,0 +1,12 @@
+CREATE TABLE "location" (
+    "id" BIGINT CONSTRAINT "pk_location" PRIMARY KEY,
+    "lat" DOUBLE PRECISION NOT NULL,
+    "lng" DOUBLE PRECISION NOT NULL,
+    "label" VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX "idx_coords" ON "location" ("lat", "lng");
+
+COMMENT ON TABLE "location" IS 'Location declares several fields per line; every exported name must
+become its own column sharing the declaration''s tag.';
+
@@ -0,0 +1,38 @@
+CREATE TABLE "ordering_warehouse" (
+    "region_id" BIGINT NOT NULL,
+    "code" VARCHAR(255) NOT NULL,
+    "name" VARCHAR(255) NOT NULL,
+    CONSTRAINT "pk_ordering_warehouse" PRIMARY KEY ("region_id", "code")
+);
+
+CREATE TABLE "ordering_product_variant" (
+    "sku" VARCHAR(255) NOT NULL,
+    "variant" VARCHAR(255) NOT NULL,
+    "barcode" VARCHAR(255) NOT NULL CONSTRAINT "uq_ordering_product_variant_barcode" UNIQUE,
+    CONSTRAINT "pk_ordering_product_variant" PRIMARY KEY ("sku", "variant")
+);
+
+CREATE TABLE "ordering_stock_level" (
+    "id" BIGINT CONSTRAINT "pk_ordering_stock_level" PRIMARY KEY,
+    "zone" VARCHAR(255) NOT NULL,
+    "bin" VARCHAR(255) NOT NULL,
+    "sku" VARCHAR(255) NOT NULL,
+    "variant" VARCHAR(255) NOT NULL,
+    "region_id" BIGINT NOT NULL,
+    "warehouse_code" VARCHAR(255) NOT NULL,
+    "lot" VARCHAR(255) NOT NULL,
+    "label" VARCHAR(255) NOT NULL,
+    "counted_at" TIMESTAMP NOT NULL,
+    "counted_by" VARCHAR(255) NOT NULL,
+    "quantity" INTEGER NOT NULL,
+    CONSTRAINT "uq_stock_location" UNIQUE ("zone", "bin"),
+    CONSTRAINT "uq_ordering_stock_level_lot_label" UNIQUE ("lot", "label"),
+    CONSTRAINT "fk_stock_variant" FOREIGN KEY ("sku", "variant") REFERENCES "ordering_product_variant" ("sku", "variant"),
+    CONSTRAINT "fk_stock_warehouse" FOREIGN KEY ("region_id", "warehouse_code") REFERENCES "ordering_warehouse" ("region_id", "code")
+);
+
+CREATE INDEX "idx_stock_zone_bin" ON "ordering_stock_level" ("zone", "bin");
+CREATE INDEX "idx_stock_lot" ON "ordering_stock_level" ("lot", "label");
+CREATE INDEX "idx_stock_audit" ON "ordering_stock_level" ("counted_at" DESC, "counted_by");
+CREATE INDEX "quantity_idx" ON "ordering_stock_level" ("quantity");
+
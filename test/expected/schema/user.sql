@@ -0,0 +1,15 @@
+CREATE TABLE "user" (
+    "id" BIGINT CONSTRAINT "pk_user" PRIMARY KEY,
+    "username" VARCHAR(255) NOT NULL CONSTRAINT "uq_user_username" UNIQUE,
+    "email" VARCHAR(255) NOT NULL,
+    "active" BOOLEAN NOT NULL,
+    "created" BIGINT NOT NULL
+);
+
+CREATE TABLE "product" (
+    "id" BIGINT CONSTRAINT "pk_product" PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL,
+    "price" DOUBLE PRECISION NOT NULL,
+    "description" VARCHAR(255) NOT NULL
+);
+
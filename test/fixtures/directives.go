@@ -0,0 +1,10 @@
+package fixtures
+
+// ShopOrder is stored in the shop schema.
+//
+//structify:table=orders schema=shop unlogged
+//structify:with="fillfactor=70"
+type ShopOrder struct {
+	ID    int64 `db:"pk"`
+	Total float64
+}
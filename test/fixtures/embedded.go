@@ -0,0 +1,24 @@
+package fixtures
+
+import "time"
+
+// BaseModel holds the columns shared by every table.
+type BaseModel struct {
+	ID        int64 `db:"pk"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Address is a value object stored inline in its parent table.
+type Address struct {
+	Street string
+	City   string `db:"index"`
+}
+
+type Customer struct {
+	BaseModel
+	Name     string
+	Shipping Address `db:"embed,prefix:ship_"`
+	Billing  Address `db:"embed"`
+	Notes    string  `db:"-"`
+}
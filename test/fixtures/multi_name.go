@@ -0,0 +1,9 @@
+package fixtures
+
+// Location declares several fields per line; every exported name must
+// become its own column sharing the declaration's tag.
+type Location struct {
+	ID          int64   `db:"pk"`
+	Lat, Lng    float64 `db:"index:idx_coords"`
+	Label, note string
+}
@@ -0,0 +1,34 @@
+package fixtures
+
+import "time"
+
+// Several indexes, unique groups and composite foreign keys on one table,
+// declared out of alphabetical order, so that the schema output shows
+// whether they follow the declaration order.
+
+type OrderingWarehouse struct {
+	RegionID int64  `db:"pk"`
+	Code     string `db:"pk"`
+	Name     string
+}
+
+type OrderingProductVariant struct {
+	SKU     string `db:"pk"`
+	Variant string `db:"pk"`
+	Barcode string `db:"unique"`
+}
+
+type OrderingStockLevel struct {
+	ID            int64     `db:"pk"`
+	Zone          string    `db:"index:idx_stock_zone_bin,unique:uq_stock_location"`
+	Bin           string    `db:"index:idx_stock_zone_bin,unique:uq_stock_location"`
+	SKU           string    `db:"fk:fk_stock_variant,ordering_product_variant,sku"`
+	Variant       string    `db:"fk:fk_stock_variant,ordering_product_variant,variant"`
+	RegionID      int64     `db:"fk:fk_stock_warehouse,ordering_warehouse,region_id"`
+	WarehouseCode string    `db:"fk:fk_stock_warehouse,ordering_warehouse,code"`
+	Lot           string    `db:"unique:uq_stock_lot,index:idx_stock_lot"`
+	Label         string    `db:"unique:uq_stock_lot,index:idx_stock_lot"`
+	CountedAt     time.Time `db:"index:idx_stock_audit,desc"`
+	CountedBy     string    `db:"index:idx_stock_audit"`
+	Quantity      int       `db:"index"`
+}
@@ -0,0 +1,20 @@
+package typed
+
+import (
+	"time"
+
+	"github.com/n0xum/structify/test/fixtures/typed/money"
+)
+
+type Account struct {
+	ID        int64 `db:"pk"`
+	Email     Email `db:"unique"`
+	Backup    *Email
+	Balance   money.Money
+	Tags      Tags
+	CreatedAt time.Time
+	Status    AccountStatus
+	Tier      Tier
+	Legacy    AccountStatus `db:"enum:old,new"`
+	Discount  Ratio
+}
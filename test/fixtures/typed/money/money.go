@@ -0,0 +1,4 @@
+package money
+
+// Money is an amount in minor currency units.
+type Money int64
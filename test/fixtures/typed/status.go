@@ -0,0 +1,28 @@
+package typed
+
+// AccountStatus is stored as text; its constants become the allowed values.
+type AccountStatus string
+
+const (
+	StatusPending AccountStatus = "pending"
+	StatusActive  AccountStatus = "active"
+	StatusClosed  AccountStatus = "closed"
+)
+
+// Tier is an iota-based enum stored as an integer.
+type Tier int
+
+const (
+	TierFree Tier = iota
+	TierPro
+	TierEnterprise
+)
+
+// Ratio is a float enum; its constants keep their decimal spelling.
+type Ratio float64
+
+const (
+	RatioNone    Ratio = 0
+	RatioTenth   Ratio = 0.1
+	RatioQuarter Ratio = 0.25
+)
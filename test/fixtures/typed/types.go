@@ -0,0 +1,7 @@
+package typed
+
+// Email is declared apart from the struct that uses it to exercise
+// package-level type resolution.
+type Email string
+
+type Tags []string
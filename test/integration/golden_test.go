@@ -0,0 +1,150 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/n0xum/structify/internal/application/command"
+	"github.com/n0xum/structify/internal/application/query"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in test/expected/schema")
+
+var goldenDir = filepath.Join("..", "expected", "schema")
+
+// TestSQLGenerationGolden compares the schema generated for each fixture
+// byte for byte with test/expected/schema/<fixture>.sql. Run with -update
+// to rewrite the golden files after an intended change.
+func TestSQLGenerationGolden(t *testing.T) {
+	fixtures := []string{
+		"composite_keys",
+		"constraints",
+		"directives",
+		"embedded",
+		"exotic_types",
+		"indexes",
+		"joins",
+		"multi_name",
+		"ordering",
+		"user",
+	}
+
+	for _, name := range fixtures {
+		t.Run(name, func(t *testing.T) {
+			qh, ch := newHandlers(t)
+			result := parseFixture(t, qh, filepath.Join(fixturesDir, name+".go"))
+
+			got, err := ch.GenerateSchema(context.Background(), &command.GenerateSchemaCommand{
+				Entities: result.EntityList,
+			})
+			if err != nil {
+				t.Fatalf("GenerateSchema error: %v", err)
+			}
+
+			golden := filepath.Join(goldenDir, name+".sql")
+			if *update {
+				if err := os.MkdirAll(goldenDir, 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("reading golden file: %v (run with -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("schema for %s differs from %s:\n%s", name, golden, lineDiff(string(want), got))
+			}
+		})
+	}
+}
+
+// TestSQLGenerationIsStable generates the schema of the same fixture many
+// times; map iteration order must not leak into the output.
+func TestSQLGenerationIsStable(t *testing.T) {
+	qh, ch := newHandlers(t)
+	result := parseFixture(t, qh, filepath.Join(fixturesDir, "ordering.go"))
+
+	var first string
+	for i := 0; i < 50; i++ {
+		got, err := ch.GenerateSchema(context.Background(), &command.GenerateSchemaCommand{
+			Entities: result.EntityList,
+		})
+		if err != nil {
+			t.Fatalf("GenerateSchema error: %v", err)
+		}
+		if i == 0 {
+			first = got
+			continue
+		}
+		if got != first {
+			t.Fatalf("run %d differs from the first run:\n%s", i, lineDiff(first, got))
+		}
+	}
+}
+
+// TestSQLGenerationIsStableAcrossPackages parses fixtures of two packages
+// afresh on every run; the packages must not come out in map order.
+func TestSQLGenerationIsStableAcrossPackages(t *testing.T) {
+	qh, ch := newHandlers(t)
+	files := []string{
+		filepath.Join(fixturesDir, "user.go"),
+		filepath.Join(fixturesDir, "typed", "account.go"),
+		filepath.Join(fixturesDir, "typed", "status.go"),
+		filepath.Join(fixturesDir, "typed", "types.go"),
+	}
+
+	var first string
+	for i := 0; i < 20; i++ {
+		result, err := qh.Parse(context.Background(), &query.ParseQuery{Files: files})
+		if err != nil {
+			t.Fatalf("Parse error: %v", err)
+		}
+		if len(result.Entities) < 2 {
+			t.Fatalf("Parse returned %d package(s), want 2", len(result.Entities))
+		}
+		got, err := ch.GenerateSchema(context.Background(), &command.GenerateSchemaCommand{
+			Entities: result.EntityList,
+		})
+		if err != nil {
+			t.Fatalf("GenerateSchema error: %v", err)
+		}
+		if i == 0 {
+			first = got
+			continue
+		}
+		if got != first {
+			t.Fatalf("run %d differs from the first run:\n%s", i, lineDiff(first, got))
+		}
+	}
+}
+
+// lineDiff reports the first line where got departs from want.
+func lineDiff(want, got string) string {
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, w, g)
+		}
+	}
+	return "(no difference)"
+}
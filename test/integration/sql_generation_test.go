@@ -94,7 +94,7 @@ func TestSQLPrimaryKey(t *testing.T) {
 		t.Fatalf("GenerateSchema error: %v", err)
 	}
 
-	if !strings.Contains(got, "BIGINT PRIMARY KEY") {
+	if !strings.Contains(got, `"id" BIGINT CONSTRAINT "pk_user" PRIMARY KEY`) {
 		t.Errorf("expected PRIMARY KEY constraint in:\n%s", got)
 	}
 }